@@ -1,40 +1,160 @@
 package scaleway
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
 	"sort"
+	"sync"
 
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/mitchellh/go-homedir"
 	"github.com/nicolai86/scaleway-sdk/api"
 )
 
+// scalewayRegions lists the regions supported by the Scaleway API
+var scalewayRegions = []string{"par1", "ams1"}
+
+// defaultScalewayConfigFile is the configuration file of the scaleway CLI
+const defaultScalewayConfigFile = "~/.scwrc"
+
 // Config contains scaleway configuration values
 type Config struct {
 	Organization string
 	APIKey       string
 	Region       string
+	MaxRetries   int
+
+	// custom API endpoints, the default Scaleway endpoints are used when empty
+	ComputeEndpoint      string
+	AccountEndpoint      string
+	MarketplaceEndpoint  string
+	AvailabilityEndpoint string
 }
 
 // Client contains scaleway api clients
 type Client struct {
+	// scaleway is the client of the region configured on the provider
 	scaleway *api.ScalewayAPI
+
+	config  Config
+	lock    sync.Mutex
+	regions map[string]*api.ScalewayAPI
 }
 
 // Client configures and returns a fully initialized Scaleway client
 func (c *Config) Client() (*Client, error) {
-	api, err := api.New(
-		c.Organization,
-		c.APIKey,
-		c.Region,
-	)
+	scaleway, err := c.regionalClient(c.Region)
 	if err != nil {
 		return nil, err
 	}
 
 	// fetch known scaleway server types to support validation in r/server
 	if len(commercialServerTypes) == 0 {
-		if availability, err := api.GetServerAvailabilities(); err == nil {
+		if availability, err := scaleway.GetServerAvailabilities(); err == nil {
 			commercialServerTypes = availability.CommercialTypes()
 			sort.StringSlice(commercialServerTypes).Sort()
 		}
 	}
-	return &Client{api}, nil
+	return &Client{
+		scaleway: scaleway,
+		config:   *c,
+		regions:  map[string]*api.ScalewayAPI{c.Region: scaleway},
+	}, nil
+}
+
+func (c *Config) regionalClient(region string) (*api.ScalewayAPI, error) {
+	options := []func(*api.ScalewayAPI){
+		api.WithHTTPClient(&http.Client{
+			Transport: newRetryTransport(cleanhttp.DefaultPooledTransport(), c.MaxRetries),
+		}),
+	}
+	if c.ComputeEndpoint != "" {
+		options = append(options, api.WithComputeAPI(c.ComputeEndpoint))
+	}
+	if c.AccountEndpoint != "" {
+		options = append(options, api.WithAccountAPI(c.AccountEndpoint))
+	}
+	if c.MarketplaceEndpoint != "" {
+		options = append(options, api.WithMarketplaceAPI(c.MarketplaceEndpoint))
+	}
+	if c.AvailabilityEndpoint != "" {
+		options = append(options, api.WithAvailabilityAPI(c.AvailabilityEndpoint))
+	}
+	return api.New(c.Organization, c.APIKey, region, options...)
+}
+
+// forRegion returns the client of the given region, creating it on first use
+func (c *Client) forRegion(region string) (*api.ScalewayAPI, error) {
+	if !isScalewayRegion(region) {
+		return nil, fmt.Errorf("Unknown region %q, expected one of %v", region, scalewayRegions)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if scaleway, ok := c.regions[region]; ok {
+		return scaleway, nil
+	}
+	scaleway, err := c.config.regionalClient(region)
+	if err != nil {
+		return nil, err
+	}
+	c.regions[region] = scaleway
+	return scaleway, nil
+}
+
+// forResource returns the client of the region of the given resource,
+// falling back to the region configured on the provider
+func (c *Client) forResource(d *schema.ResourceData) (*api.ScalewayAPI, error) {
+	if region, ok := d.GetOk("region"); ok {
+		return c.forRegion(region.(string))
+	}
+	return c.scaleway, nil
+}
+
+func isScalewayRegion(region string) bool {
+	for _, r := range scalewayRegions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// scalewayConfigFile is the content of the scaleway CLI configuration file
+type scalewayConfigFile struct {
+	Organization string `json:"organization"`
+	Token        string `json:"token"`
+	Region       string `json:"region"`
+}
+
+// readScalewayConfigFile reads the scaleway CLI configuration file at path,
+// or at ~/.scwrc when path is empty. A missing ~/.scwrc is not an error.
+func readScalewayConfigFile(path string) (*scalewayConfigFile, error) {
+	explicit := path != ""
+	if !explicit {
+		path = defaultScalewayConfigFile
+	}
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return &scalewayConfigFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading scaleway configuration file: %s", err)
+	}
+
+	var config scalewayConfigFile
+	if err := json.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("Error parsing scaleway configuration file %s: %s", path, err)
+	}
+	return &config, nil
 }
@@ -13,6 +13,7 @@ func dataSourceScalewayBootscript() *schema.Resource {
 		Read: dataSourceScalewayBootscriptRead,
 
 		Schema: map[string]*schema.Schema{
+			"region": regionSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -26,10 +27,11 @@ func dataSourceScalewayBootscript() *schema.Resource {
 				Description: "partial name of the desired bootscript to filter with",
 			},
 			"architecture": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Optional:    true,
-				Description: "architecture of the desired bootscript",
+				Type:         schema.TypeString,
+				Computed:     true,
+				Optional:     true,
+				Description:  "architecture of the desired bootscript",
+				ValidateFunc: validateArchitecture,
 			},
 			// Computed values.
 			"organization": {
@@ -80,7 +82,11 @@ func bootscriptDescriptionAttributes(d *schema.ResourceData, script api.Scaleway
 }
 
 func dataSourceScalewayBootscriptRead(d *schema.ResourceData, meta interface{}) error {
-	scaleway := meta.(*Client).scaleway
+	scaleway, err := meta.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	d.Set("region", scaleway.Region)
 
 	scripts, err := scaleway.GetBootscripts()
 	if err != nil {
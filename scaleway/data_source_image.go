@@ -3,6 +3,8 @@ package scaleway
 import (
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/nicolai86/scaleway-sdk/api"
@@ -13,6 +15,7 @@ func dataSourceScalewayImage() *schema.Resource {
 		Read: dataSourceScalewayImageRead,
 
 		Schema: map[string]*schema.Schema{
+			"region": regionSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -27,27 +30,61 @@ func dataSourceScalewayImage() *schema.Resource {
 				Description: "partial name of the desired image to filter with",
 			},
 			"architecture": {
-				Type:        schema.TypeString,
-				Required:    true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "architecture of the desired image",
+				ValidateFunc: validateArchitecture,
+			},
+			"most_recent": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
 				ForceNew:    true,
-				Description: "architecture of the desired image",
+				Description: "return the most recent image if multiple images match",
 			},
-			// Computed values.
 			"organization": {
 				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
 				Computed:    true,
-				Description: "organization owning the bootscript",
+				Description: "organization owning the image",
 			},
 			"public": {
-				Type:        schema.TypeBool,
-				Computed:    true,
-				Description: "indication if the bootscript is public",
+				// a string, so that false can be told apart from unset
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Computed:     true,
+				ValidateFunc: validateBoolString,
+				Description:  "indication if the image is public",
 			},
+			// Computed values.
 			"creation_date": {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Description: "date when the image was created",
 			},
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "name of the marketplace version of the image",
+			},
+			"current_public_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the current public marketplace version of the image",
+			},
+			"root_volume_size_in_gb": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "size of the root volume of the image in GB",
+			},
+			"default_bootscript": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the default bootscript of the image",
+			},
 		},
 	}
 }
@@ -55,18 +92,35 @@ func dataSourceScalewayImage() *schema.Resource {
 func scalewayImageAttributes(d *schema.ResourceData, img *api.ScalewayImage) error {
 	d.Set("architecture", img.Arch)
 	d.Set("organization", img.Organization)
-	d.Set("public", img.Public)
+	d.Set("public", strconv.FormatBool(img.Public))
 	d.Set("creation_date", img.CreationDate)
 	d.Set("name", img.Name)
+	d.Set("root_volume_size_in_gb", img.RootVolume.Size/gb)
+	d.Set("default_bootscript", "")
+	if img.DefaultBootscript != nil {
+		d.Set("default_bootscript", img.DefaultBootscript.Identifier)
+	}
 	d.SetId(img.Identifier)
 
 	return nil
 }
 
+// marketImageMatch references a local image matched by the image data source,
+// together with the marketplace image and version it belongs to.
+type marketImageMatch struct {
+	image   api.MarketImage
+	version api.MarketVersionDefinition
+	local   api.MarketLocalImageDefinition
+}
+
 func dataSourceScalewayImageRead(d *schema.ResourceData, meta interface{}) error {
-	scaleway := meta.(*Client).scaleway
+	scaleway, err := meta.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	d.Set("region", scaleway.Region)
 
-	var nameMatch func(api.MarketImage) bool
+	nameMatch := func(api.MarketImage) bool { return true }
 	if name, ok := d.GetOk("name"); ok {
 		nameMatch = func(img api.MarketImage) bool {
 			return img.Name == name.(string)
@@ -81,25 +135,61 @@ func dataSourceScalewayImageRead(d *schema.ResourceData, meta interface{}) error
 		}
 	}
 
+	organization := d.Get("organization").(string)
+
 	imgs, err := scaleway.GetImages()
 	if err != nil {
 		return err
 	}
-	images := []api.MarketLocalImageDefinition{}
+	images := []marketImageMatch{}
 	for _, image := range *imgs {
 		if !nameMatch(image) {
 			continue
 		}
+		if organization != "" {
+			owner := image.Organization.ID
+			if owner == "" {
+				// images of our organization carry no marketplace organization
+				owner = scaleway.Organization
+			}
+			if owner != organization {
+				continue
+			}
+		}
 
 		for _, v := range image.Versions {
 			for _, l := range v.LocalImages {
 				if l.Arch == d.Get("architecture").(string) && l.Zone == scaleway.Region {
-					images = append(images, l)
+					images = append(images, marketImageMatch{image, v, l})
 				}
 			}
 		}
 	}
 
+	if raw, ok := d.GetOk("public"); ok {
+		// the listing reports all images of our organization as private, so
+		// the visibility is read from the images themselves
+		public := raw.(string) == "true"
+		matches := []marketImageMatch{}
+		for _, match := range images {
+			img, err := scaleway.GetImage(match.local.ID)
+			if err != nil {
+				return err
+			}
+			if img.Public == public {
+				matches = append(matches, match)
+			}
+		}
+		images = matches
+	}
+
+	if len(images) > 1 && d.Get("most_recent").(bool) {
+		sort.Slice(images, func(i, j int) bool {
+			return parseCreationDate(images[i].version.CreationDate).After(parseCreationDate(images[j].version.CreationDate))
+		})
+		images = images[:1]
+	}
+
 	if len(images) > 1 {
 		return fmt.Errorf("The query returned more than one result. Please refine your query.")
 	}
@@ -107,10 +197,12 @@ func dataSourceScalewayImageRead(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("The query returned no result. Please refine your query.")
 	}
 
-	img, err := scaleway.GetImage(images[0].ID)
+	img, err := scaleway.GetImage(images[0].local.ID)
 	if err != nil {
 		return err
 	}
 
+	d.Set("version", images[0].version.Name)
+	d.Set("current_public_version", images[0].image.CurrentPublicVersion)
 	return scalewayImageAttributes(d, img)
 }
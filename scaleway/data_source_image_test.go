@@ -47,6 +47,42 @@ func TestAccScalewayDataSourceImage_Filtered(t *testing.T) {
 	})
 }
 
+func TestAccScalewayDataSourceImage_MostRecent(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewayImageMostRecentConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckImageID("data.scaleway_image.ubuntu"),
+					resource.TestCheckResourceAttr("data.scaleway_image.ubuntu", "architecture", "x86_64"),
+					resource.TestCheckResourceAttrSet("data.scaleway_image.ubuntu", "version"),
+					resource.TestCheckResourceAttrSet("data.scaleway_image.ubuntu", "current_public_version"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccScalewayDataSourceImage_Private(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewayImagePrivateConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckImageID("data.scaleway_image.private"),
+					resource.TestCheckResourceAttrPair("data.scaleway_image.private", "id", "scaleway_image.test", "id"),
+					resource.TestCheckResourceAttr("data.scaleway_image.private", "public", "false"),
+					resource.TestCheckResourceAttr("data.scaleway_image.private", "root_volume_size_in_gb", "2"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckImageID(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -82,3 +118,37 @@ data "scaleway_image" "ubuntu" {
   architecture = "arm"
 }
 `
+
+const testAccCheckScalewayImageMostRecentConfig = `
+data "scaleway_image" "ubuntu" {
+  name_filter = "Ubuntu"
+  architecture = "x86_64"
+  most_recent = true
+}
+`
+
+const testAccCheckScalewayImagePrivateConfig = `
+resource "scaleway_volume" "test" {
+  name = "test"
+  size_in_gb = 2
+  type = "l_ssd"
+}
+
+resource "scaleway_snapshot" "test" {
+  name = "test"
+  volume = "${scaleway_volume.test.id}"
+}
+
+resource "scaleway_image" "test" {
+  name = "terraform-test-private"
+  snapshot = "${scaleway_snapshot.test.id}"
+  architecture = "arm"
+}
+
+data "scaleway_image" "private" {
+  name = "${scaleway_image.test.name}"
+  architecture = "arm"
+  organization = "${scaleway_image.test.organization}"
+  public = "false"
+}
+`
@@ -0,0 +1,259 @@
+package scaleway
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/nicolai86/scaleway-sdk/api"
+)
+
+func dataSourceScalewayServer() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceScalewayServerRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": regionSchema(),
+			"id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "the ID of the desired server",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "exact name of the desired server",
+			},
+			"name_filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "partial name of the desired server to filter with",
+			},
+			"tags": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional:    true,
+				Computed:    true,
+				Description: "tags the desired server is tagged with",
+			},
+			// Computed values.
+			"image": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the base image of the server",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the instance type of the server",
+			},
+			"arch": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the architecture of the server",
+			},
+			"bootscript": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the boot configuration of the server",
+			},
+			"security_group": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the security group the server is attached to",
+			},
+			"volumes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "the volumes attached to the server, starting with the root volume",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"volume_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size_in_gb": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"enable_ipv6": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "determines if IPv6 is enabled for the server",
+			},
+			"private_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the Scaleway internal IP address of the server",
+			},
+			"public_ip": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the public IPv4 address of the server",
+			},
+			"public_ipv6": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the public IPv6 address of the server, if enabled",
+			},
+			"public_dns": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the DNS name of the public IP address of the server",
+			},
+			"private_dns": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the DNS name of the private IP address of the server",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the server state (running, stopped, standby)",
+			},
+			"state_detail": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "scaleway description of the server state",
+			},
+			"organization": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "organization owning the server",
+			},
+			"creation_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "date when the server was created",
+			},
+		},
+	}
+}
+
+func serverDescriptionAttributes(d *schema.ResourceData, server *api.ScalewayServer) error {
+	d.Set("name", server.Name)
+	d.Set("tags", server.Tags)
+	d.Set("image", server.Image.Identifier)
+	d.Set("type", server.CommercialType)
+	d.Set("arch", server.Arch)
+	if server.Bootscript != nil {
+		d.Set("bootscript", server.Bootscript.Identifier)
+	}
+	d.Set("security_group", server.SecurityGroup.Identifier)
+	d.Set("enable_ipv6", server.EnableIPV6)
+	d.Set("private_ip", server.PrivateIP)
+	d.Set("public_ip", server.PublicAddress.IP)
+	if server.EnableIPV6 && server.IPV6 != nil {
+		d.Set("public_ipv6", server.IPV6.Address)
+	}
+	d.Set("public_dns", server.DNSPublic)
+	d.Set("private_dns", server.DNSPrivate)
+	d.Set("state", serverState(server.State))
+	d.Set("state_detail", server.StateDetail)
+	d.Set("organization", server.Organization)
+	d.Set("creation_date", server.CreationDate)
+
+	volumes := []map[string]interface{}{}
+	for _, volume := range sortedServerVolumes(server) {
+		volumes = append(volumes, map[string]interface{}{
+			"volume_id":  volume.Identifier,
+			"name":       volume.Name,
+			"size_in_gb": int(volume.Size / gb),
+			"type":       volume.VolumeType,
+		})
+	}
+	d.Set("volumes", volumes)
+
+	d.SetId(server.Identifier)
+	return nil
+}
+
+func dataSourceScalewayServerRead(d *schema.ResourceData, meta interface{}) error {
+	scaleway, err := meta.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	d.Set("region", scaleway.Region)
+
+	if id, ok := d.GetOk("id"); ok {
+		server, err := scaleway.GetServer(id.(string))
+		if err != nil {
+			return err
+		}
+		return serverDescriptionAttributes(d, server)
+	}
+
+	servers, err := getServers(scaleway)
+	if err != nil {
+		return err
+	}
+
+	nameMatch := func(s api.ScalewayServer) bool { return true }
+	if name, ok := d.GetOk("name"); ok {
+		nameMatch = func(s api.ScalewayServer) bool {
+			return s.Name == name.(string)
+		}
+	} else if nameFilter, ok := d.GetOk("name_filter"); ok {
+		exp, err := regexp.Compile(nameFilter.(string))
+		if err != nil {
+			return fmt.Errorf("invalid name_filter regular expression provided: %v", err)
+		}
+		nameMatch = func(s api.ScalewayServer) bool {
+			return exp.MatchString(s.Name)
+		}
+	}
+
+	var tags []string
+	if raw, ok := d.GetOk("tags"); ok {
+		for _, tag := range raw.([]interface{}) {
+			tags = append(tags, tag.(string))
+		}
+	}
+	tagsMatch := func(s api.ScalewayServer) bool {
+		for _, tag := range tags {
+			found := false
+			for _, serverTag := range s.Tags {
+				found = found || serverTag == tag
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	}
+
+	var matches []*api.ScalewayServer
+	for i, server := range servers {
+		if nameMatch(server) && tagsMatch(server) {
+			matches = append(matches, &servers[i])
+		}
+	}
+
+	if len(matches) > 1 {
+		ids := []string{}
+		for _, match := range matches {
+			ids = append(ids, match.Identifier)
+		}
+		return fmt.Errorf("The query returned more than one result (%v). Please refine your query.", ids)
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("The query returned no result. Please refine your query.")
+	}
+
+	return serverDescriptionAttributes(d, matches[0])
+}
@@ -0,0 +1,81 @@
+package scaleway
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/nicolai86/scaleway-sdk/api"
+)
+
+func TestAccScalewayDataSourceServer_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScalewayServerDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewayServerDataSourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.scaleway_server.by_id", "name", "scaleway_server.base", "name"),
+					resource.TestCheckResourceAttrPair("data.scaleway_server.by_name", "id", "scaleway_server.base", "id"),
+					resource.TestCheckResourceAttrPair("data.scaleway_server.by_tags", "id", "scaleway_server.base", "id"),
+					resource.TestCheckResourceAttr("data.scaleway_server.by_name", "type", "C1"),
+					resource.TestCheckResourceAttr("data.scaleway_server.by_name", "image", armImageIdentifier),
+					resource.TestCheckResourceAttr("data.scaleway_server.by_name", "state", "running"),
+					resource.TestCheckResourceAttr("data.scaleway_server.by_name", "volumes.#", "1"),
+					resource.TestCheckResourceAttrPair("data.scaleway_server.by_name", "private_ip", "scaleway_server.base", "private_ip"),
+					resource.TestCheckResourceAttrPair("data.scaleway_server.by_name", "security_group", "scaleway_security_group.base", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceScalewayServerVolumesOrder(t *testing.T) {
+	server := &api.ScalewayServer{Identifier: "server", Volumes: map[string]api.ScalewayVolume{}}
+	for _, index := range []string{"10", "2", "0", "1"} {
+		server.Volumes[index] = api.ScalewayVolume{Identifier: "volume-" + index}
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceScalewayServer().Schema, map[string]interface{}{})
+	if err := serverDescriptionAttributes(d, server); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for i, index := range []string{"0", "1", "2", "10"} {
+		if id := d.Get(fmt.Sprintf("volumes.%d.volume_id", i)).(string); id != "volume-"+index {
+			t.Errorf("expected volume %d to be volume-%s, got %s", i, index, id)
+		}
+	}
+}
+
+var testAccCheckScalewayServerDataSourceConfig = fmt.Sprintf(`
+resource "scaleway_security_group" "base" {
+  name = "data-source-server"
+  description = "data-source-server"
+}
+
+resource "scaleway_server" "base" {
+  name = "terraform-data-source-server"
+  # ubuntu 14.04
+  image = "%s"
+  type = "C1"
+  tags = [ "terraform-test", "data-source" ]
+  security_group = "${scaleway_security_group.base.id}"
+}
+
+data "scaleway_server" "by_id" {
+  id = "${scaleway_server.base.id}"
+}
+
+data "scaleway_server" "by_name" {
+  name = "${scaleway_server.base.name}"
+}
+
+data "scaleway_server" "by_tags" {
+  name_filter = "^terraform-data-source"
+  tags = [ "${element(scaleway_server.base.tags, 1)}" ]
+}
+`, armImageIdentifier)
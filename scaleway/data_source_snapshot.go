@@ -0,0 +1,147 @@
+package scaleway
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/nicolai86/scaleway-sdk/api"
+)
+
+func dataSourceScalewaySnapshot() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceScalewaySnapshotRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": regionSchema(),
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Computed:    true,
+				Description: "exact name of the desired snapshot",
+			},
+			"name_filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "partial name of the desired snapshot to filter with",
+			},
+			"volume": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "the volume the desired snapshot was taken from",
+			},
+			"most_recent": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+				Description: "return the most recent snapshot if multiple snapshots match",
+			},
+			// Computed values.
+			"size_in_gb": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "the size of the snapshot in GB",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the state of the snapshot",
+			},
+			"volume_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the type of backing storage",
+			},
+			"base_volume": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the volume the snapshot was taken from",
+			},
+			"organization": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "organization owning the snapshot",
+			},
+			"creation_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "date when the snapshot was created",
+			},
+		},
+	}
+}
+
+func snapshotDescriptionAttributes(d *schema.ResourceData, snapshot api.ScalewaySnapshot) error {
+	d.Set("name", snapshot.Name)
+	d.Set("size_in_gb", snapshot.Size/gb)
+	d.Set("state", snapshot.State)
+	d.Set("volume_type", snapshot.VolumeType)
+	d.Set("base_volume", snapshot.BaseVolume.Identifier)
+	d.Set("organization", snapshot.Organization)
+	d.Set("creation_date", snapshot.CreationDate)
+	d.SetId(snapshot.Identifier)
+
+	return nil
+}
+
+func dataSourceScalewaySnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	scaleway, err := meta.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	d.Set("region", scaleway.Region)
+
+	snapshots, err := scaleway.GetSnapshots()
+	if err != nil {
+		return err
+	}
+
+	nameMatch := func(s api.ScalewaySnapshot) bool { return true }
+	if name, ok := d.GetOk("name"); ok {
+		nameMatch = func(s api.ScalewaySnapshot) bool {
+			return s.Name == name.(string)
+		}
+	} else if nameFilter, ok := d.GetOk("name_filter"); ok {
+		exp, err := regexp.Compile(nameFilter.(string))
+		if err != nil {
+			return fmt.Errorf("invalid name_filter regular expression provided: %v", err)
+		}
+		nameMatch = func(s api.ScalewaySnapshot) bool {
+			return exp.MatchString(s.Name)
+		}
+	}
+
+	volume := d.Get("volume").(string)
+
+	var matches []api.ScalewaySnapshot
+	for _, snapshot := range *snapshots {
+		if !nameMatch(snapshot) {
+			continue
+		}
+		if volume != "" && snapshot.BaseVolume.Identifier != volume {
+			continue
+		}
+		matches = append(matches, snapshot)
+	}
+
+	if len(matches) > 1 && d.Get("most_recent").(bool) {
+		sort.Slice(matches, func(i, j int) bool {
+			return parseCreationDate(matches[i].CreationDate).After(parseCreationDate(matches[j].CreationDate))
+		})
+		matches = matches[:1]
+	}
+
+	if len(matches) > 1 {
+		return fmt.Errorf("The query returned more than one result. Please refine your query.")
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("The query returned no result. Please refine your query.")
+	}
+
+	return snapshotDescriptionAttributes(d, matches[0])
+}
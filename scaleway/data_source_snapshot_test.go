@@ -0,0 +1,108 @@
+package scaleway
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccScalewayDataSourceSnapshot_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewaySnapshotDataSourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSnapshotID("data.scaleway_snapshot.test"),
+					resource.TestCheckResourceAttr("data.scaleway_snapshot.test", "name", "test"),
+					resource.TestCheckResourceAttr("data.scaleway_snapshot.test", "size_in_gb", "2"),
+					resource.TestCheckResourceAttr("data.scaleway_snapshot.test", "volume_type", "l_ssd"),
+					resource.TestCheckResourceAttrPair("data.scaleway_snapshot.test", "base_volume", "scaleway_volume.test", "id"),
+					resource.TestCheckResourceAttrSet("data.scaleway_snapshot.test", "creation_date"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccScalewayDataSourceSnapshot_MostRecent(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewaySnapshotDataSourceMostRecentConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSnapshotID("data.scaleway_snapshot.test"),
+					resource.TestCheckResourceAttrPair("data.scaleway_snapshot.test", "id", "scaleway_snapshot.second", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckSnapshotID(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Can't find snapshot data source: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("snapshot data source ID not set")
+		}
+
+		scaleway := testAccProvider.Meta().(*Client).scaleway
+		_, err := scaleway.GetSnapshot(rs.Primary.ID)
+
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+const testAccCheckScalewaySnapshotDataSourceConfig = `
+resource "scaleway_volume" "test" {
+  name = "test"
+  size_in_gb = 2
+  type = "l_ssd"
+}
+
+resource "scaleway_snapshot" "test" {
+  name = "test"
+  volume = "${scaleway_volume.test.id}"
+}
+
+data "scaleway_snapshot" "test" {
+  name = "${scaleway_snapshot.test.name}"
+}
+`
+
+const testAccCheckScalewaySnapshotDataSourceMostRecentConfig = `
+resource "scaleway_volume" "test" {
+  name = "test"
+  size_in_gb = 2
+  type = "l_ssd"
+}
+
+resource "scaleway_snapshot" "first" {
+  name = "test-first"
+  volume = "${scaleway_volume.test.id}"
+}
+
+resource "scaleway_snapshot" "second" {
+  name = "test-second"
+  volume = "${scaleway_snapshot.first.base_volume}"
+}
+
+data "scaleway_snapshot" "test" {
+  name_filter = "^test-"
+  volume = "${scaleway_snapshot.second.base_volume}"
+  most_recent = true
+}
+`
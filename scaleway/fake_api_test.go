@@ -0,0 +1,1349 @@
+package scaleway
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/nicolai86/scaleway-sdk/api"
+)
+
+const (
+	fakeOrganization            = "00000000-0000-4000-8000-000000000001"
+	fakeToken                   = "00000000-0000-4000-8000-000000000002"
+	fakeUserID                  = "00000000-0000-4000-8000-000000000003"
+	fakeMarketplaceOrganization = "00000000-0000-4000-8000-000000000004"
+)
+
+// testAccFakeAPI is the fake the acceptance tests run against, nil when they run
+// against Scaleway.
+var testAccFakeAPI *fakeScalewayAPI
+
+// testAccUseFakeAPI points the provider at an in-process fake of the Scaleway APIs,
+// so the acceptance tests can run without a Scaleway account.
+func testAccUseFakeAPI(provider *schema.Provider) {
+	testAccFakeAPI = newFakeScalewayAPI()
+	server := httptest.NewServer(testAccFakeAPI)
+
+	os.Setenv("SCALEWAY_ORGANIZATION", fakeOrganization)
+	os.Setenv("SCALEWAY_TOKEN", fakeToken)
+	stateRefreshInterval = 10 * time.Millisecond
+
+	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		config, err := providerConfig(d)
+		if err != nil {
+			return nil, err
+		}
+		config.ComputeEndpoint = server.URL + "/compute"
+		config.AccountEndpoint = server.URL + "/account"
+		config.MarketplaceEndpoint = server.URL + "/marketplace"
+		config.AvailabilityEndpoint = server.URL + "/availability"
+		return config.Client()
+	}
+}
+
+// fakeScalewayAPI keeps the objects of a single organization in memory and
+// serves them through the compute, account, marketplace and availability APIs.
+//
+// State changes happen asynchronously like on Scaleway: a server which is powered
+// on is "starting" until it has been read once, and "running" afterwards.
+type fakeScalewayAPI struct {
+	lock sync.Mutex
+
+	servers        map[string]*api.ScalewayServer
+	userData       map[string]map[string][]byte
+	volumes        map[string]*api.ScalewayVolume
+	snapshots      map[string]*api.ScalewaySnapshot
+	images         map[string]*api.ScalewayImage
+	bootscripts    map[string]*api.ScalewayBootscript
+	ips            map[string]*api.ScalewayIPDefinition
+	securityGroups map[string]*api.ScalewaySecurityGroups
+	rules          map[string][]*api.SecurityGroupRule
+	marketplace    []api.MarketImage
+	sshKeys        []api.ScalewayKeyDefinition
+
+	// transitions holds the state an object reaches once it has been observed
+	transitions map[string]string
+
+	// actions holds the actions performed on each server
+	actions map[string][]string
+}
+
+func newFakeScalewayAPI() *fakeScalewayAPI {
+	f := &fakeScalewayAPI{
+		servers:        make(map[string]*api.ScalewayServer),
+		userData:       make(map[string]map[string][]byte),
+		volumes:        make(map[string]*api.ScalewayVolume),
+		snapshots:      make(map[string]*api.ScalewaySnapshot),
+		images:         make(map[string]*api.ScalewayImage),
+		bootscripts:    make(map[string]*api.ScalewayBootscript),
+		ips:            make(map[string]*api.ScalewayIPDefinition),
+		securityGroups: make(map[string]*api.ScalewaySecurityGroups),
+		rules:          make(map[string][]*api.SecurityGroupRule),
+		sshKeys:        []api.ScalewayKeyDefinition{},
+		transitions:    make(map[string]string),
+		actions:        make(map[string][]string),
+	}
+	f.seed()
+	return f
+}
+
+// seed creates the public bootscripts and images the acceptance tests rely on,
+// as well as the default security group of the organization.
+func (f *fakeScalewayAPI) seed() {
+	for _, bootscript := range []api.ScalewayBootscript{
+		{Identifier: fakeUUID(), Title: "armv7l mainline 4.9.20 rev1", Arch: "arm", Public: true, Default: true},
+		{Identifier: fakeUUID(), Title: "armv7l Rescue 4.9.20 rev1", Arch: "arm", Public: true},
+		{Identifier: fakeUUID(), Title: "x86_64 mainline 4.9.20 rev1", Arch: "x86_64", Public: true, Default: true},
+		{Identifier: fakeUUID(), Title: "x86_64 Rescue 4.9.20 rev1", Arch: "x86_64", Public: true},
+	} {
+		bootscript := bootscript
+		bootscript.Organization = fakeMarketplaceOrganization
+		f.bootscripts[bootscript.Identifier] = &bootscript
+	}
+
+	f.seedMarketplaceImage("Ubuntu Precise (12.04)", "2014-05-13T10:00:00.000000+00:00", map[string]string{
+		"arm/par1":    armImageIdentifier,
+		"arm/ams1":    fakeUUID(),
+		"x86_64/par1": fakeUUID(),
+		"x86_64/ams1": fakeUUID(),
+	})
+	f.seedMarketplaceImage("Ubuntu Xenial (16.04 latest)", "2017-10-20T10:00:00.000000+00:00", map[string]string{
+		"arm/par1":    fakeUUID(),
+		"arm/ams1":    fakeUUID(),
+		"x86_64/par1": fakeUUID(),
+		"x86_64/ams1": fakeUUID(),
+	})
+	// the marketplace names images after their distribution, not their release
+	f.marketplace[0].Name = "Ubuntu Precise"
+
+	group := &api.ScalewaySecurityGroups{
+		ID:                    fakeUUID(),
+		Name:                  "Default security group",
+		Description:           "Auto generated security group.",
+		Organization:          fakeOrganization,
+		EnableDefaultSecurity: true,
+		OrganizationDefault:   true,
+	}
+	f.securityGroups[group.ID] = group
+}
+
+// seedMarketplaceImage adds a marketplace image whose local images, keyed by
+// architecture and zone, are public images of the compute API.
+func (f *fakeScalewayAPI) seedMarketplaceImage(name, creationDate string, localImages map[string]string) {
+	version := api.MarketVersionDefinition{
+		ID:               fakeUUID(),
+		Name:             creationDate[:10],
+		CreationDate:     creationDate,
+		ModificationDate: creationDate,
+	}
+	for key, id := range localImages {
+		parts := strings.SplitN(key, "/", 2)
+		version.LocalImages = append(version.LocalImages, api.MarketLocalImageDefinition{
+			ID:   id,
+			Arch: parts[0],
+			Zone: parts[1],
+		})
+
+		image := &api.ScalewayImage{
+			Identifier:        id,
+			Name:              name,
+			Arch:              parts[0],
+			Public:            true,
+			Organization:      fakeMarketplaceOrganization,
+			CreationDate:      creationDate,
+			ModificationDate:  creationDate,
+			DefaultBootscript: f.defaultBootscript(parts[0]),
+			RootVolume: api.ScalewayVolume{
+				Identifier: fakeUUID(),
+				Name:       name,
+				Size:       50 * gb,
+				VolumeType: "l_ssd",
+			},
+		}
+		f.images[id] = image
+	}
+
+	image := api.MarketImage{
+		ID:                   fakeUUID(),
+		Name:                 name,
+		Categories:           []string{"distribution"},
+		CreationDate:         creationDate,
+		ModificationDate:     creationDate,
+		CurrentPublicVersion: version.ID,
+		MarketVersions: api.MarketVersions{
+			Versions: []api.MarketVersionDefinition{version},
+		},
+	}
+	image.Organization.ID = fakeMarketplaceOrganization
+	image.Organization.Name = "Scaleway"
+	f.marketplace = append(f.marketplace, image)
+}
+
+func (f *fakeScalewayAPI) defaultBootscript(arch string) *api.ScalewayBootscript {
+	for _, bootscript := range f.bootscripts {
+		if bootscript.Arch == arch && bootscript.Default {
+			return bootscript
+		}
+	}
+	return nil
+}
+
+func (f *fakeScalewayAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	// the SDK sends a HEAD request to find out about pagination before every GET
+	if r.Method == "HEAD" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch path[0] {
+	case "compute":
+		f.serveCompute(w, r, path[1:])
+	case "account":
+		f.serveAccount(w, r, path[1:])
+	case "marketplace":
+		f.serveMarketplace(w, r, path[1:])
+	case "availability":
+		fakeJSON(w, http.StatusOK, map[string]interface{}{
+			"C1": true, "C2S": true, "C2M": true, "C2L": true,
+			"VC1S": true, "VC1M": true, "VC1L": true,
+			"ARM64-2GB": true, "X64-2GB": true,
+		})
+	default:
+		fakeNotFound(w)
+	}
+}
+
+func (f *fakeScalewayAPI) serveCompute(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		fakeNotFound(w)
+		return
+	}
+
+	switch path[0] {
+	case "servers":
+		f.serveServers(w, r, path[1:])
+	case "volumes":
+		f.serveVolumes(w, r, path[1:])
+	case "snapshots":
+		f.serveSnapshots(w, r, path[1:])
+	case "images":
+		f.serveImages(w, r, path[1:])
+	case "bootscripts":
+		f.serveBootscripts(w, r, path[1:])
+	case "ips":
+		f.serveIPs(w, r, path[1:])
+	case "security_groups":
+		f.serveSecurityGroups(w, r, path[1:])
+	default:
+		fakeNotFound(w)
+	}
+}
+
+func (f *fakeScalewayAPI) serveServers(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case "GET":
+			servers := []api.ScalewayServer{}
+			for _, id := range sortedKeys(f.servers) {
+				servers = append(servers, *f.servers[id])
+			}
+			for _, server := range servers {
+				f.observe(server.Identifier)
+			}
+			fakeJSON(w, http.StatusOK, api.ScalewayServers{Servers: servers})
+		case "POST":
+			f.createServer(w, r)
+		default:
+			fakeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	server, ok := f.servers[path[0]]
+	if !ok {
+		fakeNotFound(w)
+		return
+	}
+
+	if len(path) > 1 {
+		switch path[1] {
+		case "action":
+			f.serverAction(w, r, server)
+		case "user_data":
+			f.serveUserData(w, r, server, path[2:])
+		default:
+			fakeNotFound(w)
+		}
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		fakeJSON(w, http.StatusOK, api.ScalewayOneServer{Server: *server})
+		f.observe(server.Identifier)
+	case "PATCH":
+		f.patchServer(w, r, server)
+	case "DELETE":
+		if server.State != "stopped" {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", "server should be stopped")
+			return
+		}
+		f.removeServer(server, false)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeMethodNotAllowed(w)
+	}
+}
+
+func (f *fakeScalewayAPI) createServer(w http.ResponseWriter, r *http.Request) {
+	var definition scalewayServerDefinition
+	if !fakeDecode(w, r, &definition) {
+		return
+	}
+
+	// servers are created from an image, or from an existing root volume
+	image := &api.ScalewayImage{Arch: commercialTypeArch(definition.CommercialType)}
+	if definition.Image != nil {
+		var ok bool
+		image, ok = f.images[*definition.Image]
+		if !ok {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("image %q not found", *definition.Image))
+			return
+		}
+	} else if _, ok := definition.Volumes["0"].(string); !ok {
+		fakeError(w, http.StatusBadRequest, "invalid_request_error", "image or root volume is required")
+		return
+	} else if definition.Bootscript == nil {
+		fakeError(w, http.StatusBadRequest, "invalid_request_error", "bootscript is required without image")
+		return
+	}
+
+	if commercialTypeArch(definition.CommercialType) != image.Arch {
+		fakeError(w, http.StatusBadRequest, "invalid_request_error", "commercial type does not match the architecture of the image")
+		return
+	}
+
+	now := fakeNow()
+	server := &api.ScalewayServer{
+		Identifier:        fakeUUID(),
+		Name:              definition.Name,
+		Hostname:          definition.Name,
+		Arch:              image.Arch,
+		Image:             api.ScalewayImage{Identifier: image.Identifier, Name: image.Name},
+		CommercialType:    definition.CommercialType,
+		Organization:      definition.Organization,
+		Tags:              definition.Tags,
+		DynamicIPRequired: definition.DynamicIPRequired,
+		EnableIPV6:        definition.EnableIPV6,
+		State:             "stopped",
+		StateDetail:       "",
+		CreationDate:      now,
+		ModificationDate:  now,
+		Volumes:           make(map[string]api.ScalewayVolume),
+		Bootscript:        image.DefaultBootscript,
+	}
+
+	if definition.Bootscript != nil {
+		bootscript, ok := f.bootscripts[*definition.Bootscript]
+		if !ok {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("bootscript %q not found", *definition.Bootscript))
+			return
+		}
+		server.Bootscript = bootscript
+	}
+
+	groupID := definition.SecurityGroup
+	if groupID == "" {
+		for id, group := range f.securityGroups {
+			if group.OrganizationDefault {
+				groupID = id
+			}
+		}
+	}
+	group, ok := f.securityGroups[groupID]
+	if !ok {
+		fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("security group %q not found", groupID))
+		return
+	}
+	server.SecurityGroup = api.ScalewaySecurityGroup{Identifier: group.ID, Name: group.Name}
+
+	root := &api.ScalewayVolume{
+		Identifier:       fakeUUID(),
+		Name:             image.RootVolume.Name,
+		Size:             image.RootVolume.Size,
+		VolumeType:       image.RootVolume.VolumeType,
+		Organization:     definition.Organization,
+		CreationDate:     now,
+		ModificationDate: now,
+	}
+
+	// volumes are either IDs of existing volumes or definitions of volumes to create
+	for index, v := range definition.Volumes {
+		if volumeID, ok := v.(string); ok {
+			volume, ok := f.volumes[volumeID]
+			if !ok {
+				fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("volume %q not found", volumeID))
+				return
+			}
+			if volume.Server != nil {
+				fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("volume %q is already attached", volumeID))
+				return
+			}
+			server.Volumes[index] = *volume
+			continue
+		}
+
+		var volume scalewayServerVolumeDefinition
+		raw, _ := json.Marshal(v)
+		json.Unmarshal(raw, &volume)
+		if index != "0" {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", "only the root volume can be created along with the server")
+			return
+		}
+		if volume.Size != 0 {
+			if volume.Size < image.RootVolume.Size {
+				fakeError(w, http.StatusBadRequest, "invalid_request_error", "root volume is smaller than the image")
+				return
+			}
+			root.Size = volume.Size
+		}
+		if volume.VolumeType != "" {
+			root.VolumeType = volume.VolumeType
+		}
+		if volume.Name != "" {
+			root.Name = volume.Name
+		}
+	}
+
+	if _, ok := server.Volumes["0"]; !ok {
+		f.volumes[root.Identifier] = root
+		server.Volumes["0"] = *root
+	}
+
+	f.servers[server.Identifier] = server
+	f.userData[server.Identifier] = make(map[string][]byte)
+	f.attachVolumes(server)
+
+	if definition.PublicIP != "" {
+		ip, ok := f.ips[definition.PublicIP]
+		if !ok {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("ip %q not found", definition.PublicIP))
+			return
+		}
+		f.attachIP(ip, server)
+	}
+
+	fakeJSON(w, http.StatusCreated, api.ScalewayOneServer{Server: *server})
+}
+
+func (f *fakeScalewayAPI) serverAction(w http.ResponseWriter, r *http.Request, server *api.ScalewayServer) {
+	var action api.ScalewayServerAction
+	if !fakeDecode(w, r, &action) {
+		return
+	}
+
+	switch action.Action {
+	case "poweron":
+		if server.State != "stopped" && server.State != "stopped in place" {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", "server should be stopped")
+			return
+		}
+		server.State = "starting"
+		server.StateDetail = "provisioning node"
+		f.transitions[server.Identifier] = "running"
+	case "poweroff", "terminate":
+		if server.State != "running" && server.State != "stopped in place" {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", "server should be running")
+			return
+		}
+		server.State = "stopping"
+		server.StateDetail = "stopping"
+		f.transitions[server.Identifier] = "stopped"
+		if action.Action == "terminate" {
+			f.transitions[server.Identifier] = "terminated"
+		}
+	case "stop_in_place":
+		if server.State != "running" {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", "server should be running")
+			return
+		}
+		server.State = "stopping"
+		server.StateDetail = "stopping"
+		f.transitions[server.Identifier] = "stopped in place"
+	case "reboot":
+		if server.State != "running" {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", "server should be running")
+			return
+		}
+		server.State = "starting"
+		server.StateDetail = "rebooting"
+		f.transitions[server.Identifier] = "running"
+	default:
+		fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("unknown action %q", action.Action))
+		return
+	}
+	f.actions[server.Identifier] = append(f.actions[server.Identifier], action.Action)
+
+	w.WriteHeader(http.StatusAccepted)
+	w.Write([]byte(`{"task": {}}`))
+}
+
+// serverActions returns the actions performed on the server.
+func (f *fakeScalewayAPI) serverActions(id string) []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]string{}, f.actions[id]...)
+}
+
+// observe completes the pending state change of an object after it has been read.
+func (f *fakeScalewayAPI) observe(id string) {
+	state, ok := f.transitions[id]
+	if !ok {
+		return
+	}
+	delete(f.transitions, id)
+
+	if snapshot, ok := f.snapshots[id]; ok {
+		snapshot.State = state
+		return
+	}
+
+	server := f.servers[id]
+	switch state {
+	case "running":
+		// the kernel is still booting once the server is running
+		server.State = "running"
+		server.StateDetail = "booting kernel"
+		f.transitions[server.Identifier] = "booted"
+		server.PrivateIP = "10.1.0.1"
+		server.Location.ZoneID = "par1"
+		server.Location.Platform = "13"
+		server.Location.Cluster = "5"
+		server.Location.Hypervisor = "42"
+		server.Location.Node = "7"
+		if server.PublicAddress.Identifier == "" && server.DynamicIPRequired != nil && *server.DynamicIPRequired {
+			server.PublicAddress = api.ScalewayIPAddress{IP: "51.15.0.1", Dynamic: Bool(true)}
+		}
+		if server.EnableIPV6 {
+			server.IPV6 = &api.ScalewayIPV6Definition{
+				Address: "2001:bc8:4400:2000::1",
+				Gateway: "2001:bc8:4400:2000::",
+				Netmask: "127",
+			}
+		}
+	case "stopped":
+		server.State = "stopped"
+		server.StateDetail = ""
+		server.PrivateIP = ""
+		server.IPV6 = nil
+		server.Location = api.ScalewayServer{}.Location
+		if server.PublicAddress.Dynamic != nil && *server.PublicAddress.Dynamic {
+			server.PublicAddress = api.ScalewayIPAddress{}
+		}
+	case "booted":
+		server.StateDetail = "booted"
+	case "stopped in place":
+		// the server keeps its node, and with it its addresses
+		server.State = "stopped in place"
+		server.StateDetail = "stopped in place"
+	case "terminated":
+		f.removeServer(server, true)
+	}
+}
+
+func (f *fakeScalewayAPI) patchServer(w http.ResponseWriter, r *http.Request, server *api.ScalewayServer) {
+	var patch struct {
+		api.ScalewayServerPatchDefinition
+		CommercialType *string `json:"commercial_type"`
+	}
+	if !fakeDecode(w, r, &patch) {
+		return
+	}
+
+	if patch.Volumes != nil {
+		if server.State != "stopped" {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", "server should be stopped")
+			return
+		}
+		volumes := make(map[string]api.ScalewayVolume)
+		for index, v := range *patch.Volumes {
+			volume, ok := f.volumes[v.Identifier]
+			if !ok {
+				fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("volume %q not found", v.Identifier))
+				return
+			}
+			if volume.Server != nil && volume.Server.Identifier != server.Identifier {
+				fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("volume %q is already attached", v.Identifier))
+				return
+			}
+			volumes[index] = *volume
+		}
+		f.detachVolumes(server)
+		server.Volumes = volumes
+		f.attachVolumes(server)
+	}
+
+	if patch.CommercialType != nil {
+		if server.State != "stopped" {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", "server should be stopped")
+			return
+		}
+		if commercialTypeArch(*patch.CommercialType) != server.Arch {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", "commercial type does not match the architecture of the server")
+			return
+		}
+		server.CommercialType = *patch.CommercialType
+	}
+
+	if patch.Bootscript != nil {
+		bootscript, ok := f.bootscripts[*patch.Bootscript]
+		if !ok {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("bootscript %q not found", *patch.Bootscript))
+			return
+		}
+		server.Bootscript = bootscript
+	}
+
+	if patch.SecurityGroup != nil {
+		group, ok := f.securityGroups[patch.SecurityGroup.Identifier]
+		if !ok {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("security group %q not found", patch.SecurityGroup.Identifier))
+			return
+		}
+		server.SecurityGroup = api.ScalewaySecurityGroup{Identifier: group.ID, Name: group.Name}
+	}
+
+	if patch.Name != nil {
+		server.Name = *patch.Name
+		server.Hostname = *patch.Name
+	}
+	if patch.Tags != nil {
+		server.Tags = *patch.Tags
+	}
+	if patch.EnableIPV6 != nil {
+		server.EnableIPV6 = *patch.EnableIPV6
+	}
+	if patch.DynamicIPRequired != nil {
+		server.DynamicIPRequired = patch.DynamicIPRequired
+	}
+	server.ModificationDate = fakeNow()
+
+	fakeJSON(w, http.StatusOK, api.ScalewayOneServer{Server: *server})
+}
+
+// removeServer deletes a server. Terminated servers take their volumes with them,
+// deleted servers leave them detached.
+func (f *fakeScalewayAPI) removeServer(server *api.ScalewayServer, terminate bool) {
+	f.detachVolumes(server)
+	if terminate {
+		for _, volume := range server.Volumes {
+			delete(f.volumes, volume.Identifier)
+		}
+	}
+	for _, ip := range f.ips {
+		if ip.Server != nil && ip.Server.Identifier == server.Identifier {
+			ip.Server = nil
+		}
+	}
+	delete(f.servers, server.Identifier)
+	delete(f.userData, server.Identifier)
+	delete(f.transitions, server.Identifier)
+}
+
+func (f *fakeScalewayAPI) attachVolumes(server *api.ScalewayServer) {
+	for index, v := range server.Volumes {
+		volume := f.volumes[v.Identifier]
+		volume.Server = &struct {
+			Identifier string `json:"id,omitempty"`
+			Name       string `json:"name,omitempty"`
+		}{Identifier: server.Identifier, Name: server.Name}
+		server.Volumes[index] = *volume
+	}
+}
+
+func (f *fakeScalewayAPI) detachVolumes(server *api.ScalewayServer) {
+	for _, v := range server.Volumes {
+		if volume, ok := f.volumes[v.Identifier]; ok {
+			volume.Server = nil
+		}
+	}
+}
+
+func (f *fakeScalewayAPI) serveUserData(w http.ResponseWriter, r *http.Request, server *api.ScalewayServer, path []string) {
+	userData := f.userData[server.Identifier]
+
+	if len(path) == 0 {
+		keys := []string{}
+		for key := range userData {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fakeJSON(w, http.StatusOK, api.ScalewayUserdatas{UserData: keys})
+		return
+	}
+
+	key := path[0]
+	switch r.Method {
+	case "GET":
+		value, ok := userData[key]
+		if !ok {
+			fakeNotFound(w)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(value)
+	case "PATCH":
+		value, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+			return
+		}
+		userData[key] = value
+		w.WriteHeader(http.StatusNoContent)
+	case "DELETE":
+		if _, ok := userData[key]; !ok {
+			fakeNotFound(w)
+			return
+		}
+		delete(userData, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeMethodNotAllowed(w)
+	}
+}
+
+func (f *fakeScalewayAPI) serveVolumes(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case "GET":
+			volumes := []api.ScalewayVolume{}
+			for _, id := range sortedKeys(f.volumes) {
+				volumes = append(volumes, *f.volumes[id])
+			}
+			fakeJSON(w, http.StatusOK, map[string]interface{}{"volumes": volumes})
+		case "POST":
+			var definition scalewayVolumeDefinition
+			if !fakeDecode(w, r, &definition) {
+				return
+			}
+			if definition.BaseSnapshot != "" {
+				snapshot, ok := f.snapshots[definition.BaseSnapshot]
+				if !ok {
+					fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("snapshot %q not found", definition.BaseSnapshot))
+					return
+				}
+				if definition.Size != 0 && definition.Size != snapshot.Size {
+					fakeError(w, http.StatusBadRequest, "invalid_request_error", "size does not match the size of the snapshot")
+					return
+				}
+				definition.Size = snapshot.Size
+			}
+			if definition.Size == 0 {
+				fakeError(w, http.StatusBadRequest, "invalid_request_error", "size is required")
+				return
+			}
+			now := fakeNow()
+			volume := &api.ScalewayVolume{
+				Identifier:       fakeUUID(),
+				Name:             definition.Name,
+				Size:             definition.Size,
+				VolumeType:       definition.Type,
+				Organization:     definition.Organization,
+				CreationDate:     now,
+				ModificationDate: now,
+			}
+			f.volumes[volume.Identifier] = volume
+			fakeJSON(w, http.StatusCreated, map[string]interface{}{"volume": volume})
+		default:
+			fakeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	volume, ok := f.volumes[path[0]]
+	if !ok {
+		fakeNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		fakeJSON(w, http.StatusOK, map[string]interface{}{"volume": volume})
+	case "PUT":
+		var definition api.ScalewayVolumePutDefinition
+		if !fakeDecode(w, r, &definition) {
+			return
+		}
+		if definition.Name != nil {
+			volume.Name = *definition.Name
+		}
+		volume.ModificationDate = fakeNow()
+		fakeJSON(w, http.StatusOK, map[string]interface{}{"volume": volume})
+	case "DELETE":
+		if volume.Server != nil {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", "a server is attached to this volume")
+			return
+		}
+		delete(f.volumes, volume.Identifier)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeMethodNotAllowed(w)
+	}
+}
+
+func (f *fakeScalewayAPI) serveSnapshots(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case "GET":
+			snapshots := []api.ScalewaySnapshot{}
+			for _, id := range sortedKeys(f.snapshots) {
+				snapshots = append(snapshots, *f.snapshots[id])
+			}
+			for _, snapshot := range snapshots {
+				f.observe(snapshot.Identifier)
+			}
+			fakeJSON(w, http.StatusOK, api.ScalewaySnapshots{Snapshots: snapshots})
+		case "POST":
+			var definition api.ScalewaySnapshotDefinition
+			if !fakeDecode(w, r, &definition) {
+				return
+			}
+			volume, ok := f.volumes[definition.VolumeIDentifier]
+			if !ok {
+				fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("volume %q not found", definition.VolumeIDentifier))
+				return
+			}
+			now := fakeNow()
+			snapshot := &api.ScalewaySnapshot{
+				Identifier:       fakeUUID(),
+				Name:             definition.Name,
+				Organization:     definition.Organization,
+				Size:             volume.Size,
+				VolumeType:       volume.VolumeType,
+				State:            "snapshotting",
+				CreationDate:     now,
+				ModificationDate: now,
+				BaseVolume:       api.ScalewayVolume{Identifier: volume.Identifier, Name: volume.Name},
+			}
+			f.snapshots[snapshot.Identifier] = snapshot
+			f.transitions[snapshot.Identifier] = "available"
+			fakeJSON(w, http.StatusCreated, api.ScalewayOneSnapshot{Snapshot: *snapshot})
+		default:
+			fakeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	snapshot, ok := f.snapshots[path[0]]
+	if !ok {
+		fakeNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		fakeJSON(w, http.StatusOK, api.ScalewayOneSnapshot{Snapshot: *snapshot})
+		f.observe(snapshot.Identifier)
+	case "DELETE":
+		for _, image := range f.images {
+			if image.RootVolume.Identifier == snapshot.Identifier {
+				fakeError(w, http.StatusBadRequest, "invalid_request_error", "snapshot is used by an image")
+				return
+			}
+		}
+		delete(f.snapshots, snapshot.Identifier)
+		delete(f.transitions, snapshot.Identifier)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeMethodNotAllowed(w)
+	}
+}
+
+func (f *fakeScalewayAPI) serveImages(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case "GET":
+			organization := r.URL.Query().Get("organization")
+			images := []api.ScalewayImage{}
+			for _, id := range sortedKeys(f.images) {
+				if organization == "" || f.images[id].Organization == organization {
+					images = append(images, *f.images[id])
+				}
+			}
+			fakeJSON(w, http.StatusOK, api.ScalewayImages{Images: images})
+		case "POST":
+			var definition api.ScalewayImageDefinition
+			if !fakeDecode(w, r, &definition) {
+				return
+			}
+			snapshot, ok := f.snapshots[definition.SnapshotIDentifier]
+			if !ok {
+				fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("snapshot %q not found", definition.SnapshotIDentifier))
+				return
+			}
+			if snapshot.State != "available" {
+				fakeError(w, http.StatusBadRequest, "invalid_request_error", "snapshot is not available")
+				return
+			}
+			now := fakeNow()
+			image := &api.ScalewayImage{
+				Identifier:       fakeUUID(),
+				Name:             definition.Name,
+				Arch:             definition.Arch,
+				Organization:     definition.Organization,
+				CreationDate:     now,
+				ModificationDate: now,
+				RootVolume: api.ScalewayVolume{
+					Identifier: snapshot.Identifier,
+					Name:       snapshot.Name,
+					Size:       snapshot.Size,
+					VolumeType: snapshot.VolumeType,
+				},
+			}
+			if definition.DefaultBootscript != nil {
+				bootscript, ok := f.bootscripts[*definition.DefaultBootscript]
+				if !ok {
+					fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("bootscript %q not found", *definition.DefaultBootscript))
+					return
+				}
+				image.DefaultBootscript = bootscript
+			}
+			f.images[image.Identifier] = image
+			fakeJSON(w, http.StatusCreated, api.ScalewayOneImage{Image: *image})
+		default:
+			fakeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	image, ok := f.images[path[0]]
+	if !ok {
+		fakeNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		fakeJSON(w, http.StatusOK, api.ScalewayOneImage{Image: *image})
+	case "DELETE":
+		if image.Public {
+			fakeError(w, http.StatusForbidden, "authorization_required", "public images can not be deleted")
+			return
+		}
+		delete(f.images, image.Identifier)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeMethodNotAllowed(w)
+	}
+}
+
+func (f *fakeScalewayAPI) serveBootscripts(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		bootscripts := []api.ScalewayBootscript{}
+		for _, id := range sortedKeys(f.bootscripts) {
+			bootscripts = append(bootscripts, *f.bootscripts[id])
+		}
+		fakeJSON(w, http.StatusOK, api.ScalewayBootscripts{Bootscripts: bootscripts})
+		return
+	}
+
+	bootscript, ok := f.bootscripts[path[0]]
+	if !ok {
+		fakeNotFound(w)
+		return
+	}
+	fakeJSON(w, http.StatusOK, api.ScalewayOneBootscript{Bootscript: *bootscript})
+}
+
+func (f *fakeScalewayAPI) serveIPs(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case "GET":
+			ips := []api.ScalewayIPDefinition{}
+			for _, id := range sortedKeys(f.ips) {
+				ips = append(ips, *f.ips[id])
+			}
+			fakeJSON(w, http.StatusOK, api.ScalewayGetIPS{IPS: ips})
+		case "POST":
+			var definition struct {
+				Organization string `json:"organization"`
+			}
+			if !fakeDecode(w, r, &definition) {
+				return
+			}
+			ip := &api.ScalewayIPDefinition{
+				ID:           fakeUUID(),
+				Organization: definition.Organization,
+				Address:      fmt.Sprintf("51.15.%d.%d", len(f.ips)/250+1, len(f.ips)%250+2),
+			}
+			f.ips[ip.ID] = ip
+			fakeJSON(w, http.StatusCreated, api.ScalewayGetIP{IP: *ip})
+		default:
+			fakeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	ip, ok := f.ips[path[0]]
+	if !ok {
+		fakeNotFound(w)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		fakeJSON(w, http.StatusOK, api.ScalewayGetIP{IP: *ip})
+	case "PUT":
+		// the server is sent as an ID when attaching, and as null when detaching
+		var definition struct {
+			Server json.RawMessage `json:"server"`
+		}
+		if !fakeDecode(w, r, &definition) {
+			return
+		}
+		var serverID string
+		if err := json.Unmarshal(definition.Server, &serverID); err != nil {
+			var server struct {
+				Identifier string `json:"id"`
+			}
+			json.Unmarshal(definition.Server, &server)
+			serverID = server.Identifier
+		}
+
+		f.detachIP(ip)
+		if serverID != "" {
+			server, ok := f.servers[serverID]
+			if !ok {
+				fakeError(w, http.StatusBadRequest, "invalid_request_error", fmt.Sprintf("server %q not found", serverID))
+				return
+			}
+			f.attachIP(ip, server)
+		}
+		fakeJSON(w, http.StatusOK, api.ScalewayGetIP{IP: *ip})
+	case "DELETE":
+		f.detachIP(ip)
+		delete(f.ips, ip.ID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeMethodNotAllowed(w)
+	}
+}
+
+func (f *fakeScalewayAPI) attachIP(ip *api.ScalewayIPDefinition, server *api.ScalewayServer) {
+	for _, other := range f.ips {
+		if other.Server != nil && other.Server.Identifier == server.Identifier {
+			other.Server = nil
+		}
+	}
+	ip.Server = &struct {
+		Identifier string `json:"id,omitempty"`
+		Name       string `json:"name,omitempty"`
+	}{Identifier: server.Identifier, Name: server.Name}
+	server.PublicAddress = api.ScalewayIPAddress{Identifier: ip.ID, IP: ip.Address, Dynamic: Bool(false)}
+}
+
+func (f *fakeScalewayAPI) detachIP(ip *api.ScalewayIPDefinition) {
+	if ip.Server == nil {
+		return
+	}
+	if server, ok := f.servers[ip.Server.Identifier]; ok {
+		server.PublicAddress = api.ScalewayIPAddress{}
+	}
+	ip.Server = nil
+}
+
+func (f *fakeScalewayAPI) serveSecurityGroups(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 0 {
+		switch r.Method {
+		case "GET":
+			groups := []api.ScalewaySecurityGroups{}
+			for _, id := range sortedKeys(f.securityGroups) {
+				groups = append(groups, f.securityGroup(id))
+			}
+			fakeJSON(w, http.StatusOK, api.ScalewayGetSecurityGroups{SecurityGroups: groups})
+		case "POST":
+			var definition api.ScalewayNewSecurityGroup
+			if !fakeDecode(w, r, &definition) {
+				return
+			}
+			group := &api.ScalewaySecurityGroups{
+				ID:           fakeUUID(),
+				Name:         definition.Name,
+				Description:  definition.Description,
+				Organization: definition.Organization,
+			}
+			f.securityGroups[group.ID] = group
+			fakeJSON(w, http.StatusCreated, api.ScalewayGetSecurityGroup{SecurityGroups: f.securityGroup(group.ID)})
+		default:
+			fakeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	group, ok := f.securityGroups[path[0]]
+	if !ok {
+		fakeNotFound(w)
+		return
+	}
+
+	if len(path) > 1 && path[1] == "rules" {
+		f.serveSecurityGroupRules(w, r, group, path[2:])
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		fakeJSON(w, http.StatusOK, api.ScalewayGetSecurityGroup{SecurityGroups: f.securityGroup(group.ID)})
+	case "PUT":
+		var definition api.ScalewayUpdateSecurityGroup
+		if !fakeDecode(w, r, &definition) {
+			return
+		}
+		group.Name = definition.Name
+		group.Description = definition.Description
+		for _, server := range f.servers {
+			if server.SecurityGroup.Identifier == group.ID {
+				server.SecurityGroup.Name = group.Name
+			}
+		}
+		fakeJSON(w, http.StatusOK, api.ScalewayGetSecurityGroup{SecurityGroups: f.securityGroup(group.ID)})
+	case "DELETE":
+		if len(f.securityGroup(group.ID).Servers) > 0 {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", "group is in use. you cannot delete it.")
+			return
+		}
+		delete(f.securityGroups, group.ID)
+		delete(f.rules, group.ID)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeMethodNotAllowed(w)
+	}
+}
+
+// securityGroup returns the security group with the servers using it.
+func (f *fakeScalewayAPI) securityGroup(id string) api.ScalewaySecurityGroups {
+	group := *f.securityGroups[id]
+	group.Servers = []api.ScalewaySecurityGroup{}
+	for _, serverID := range sortedKeys(f.servers) {
+		server := f.servers[serverID]
+		if server.SecurityGroup.Identifier == id {
+			group.Servers = append(group.Servers, api.ScalewaySecurityGroup{Identifier: server.Identifier, Name: server.Name})
+		}
+	}
+	return group
+}
+
+func (f *fakeScalewayAPI) serveSecurityGroupRules(w http.ResponseWriter, r *http.Request, group *api.ScalewaySecurityGroups, path []string) {
+	rules := f.rules[group.ID]
+
+	if len(path) == 0 {
+		switch r.Method {
+		case "GET":
+			list := []api.SecurityGroupRule{}
+			for _, rule := range rules {
+				list = append(list, *rule)
+			}
+			fakeJSON(w, http.StatusOK, api.GetSecurityGroupRules{Rules: list})
+		case "POST":
+			var definition api.NewSecurityGroupRule
+			if !fakeDecode(w, r, &definition) {
+				return
+			}
+			rule := &api.SecurityGroupRule{
+				ID:           fakeUUID(),
+				Action:       definition.Action,
+				Direction:    definition.Direction,
+				IPRange:      definition.IPRange,
+				Protocol:     definition.Protocol,
+				DestPortFrom: definition.DestPortFrom,
+				Position:     len(rules) + 1,
+				Editable:     true,
+			}
+			f.rules[group.ID] = append(rules, rule)
+			fakeJSON(w, http.StatusCreated, api.GetSecurityGroupRule{Rules: *rule})
+		default:
+			fakeMethodNotAllowed(w)
+		}
+		return
+	}
+
+	index := -1
+	for i, rule := range rules {
+		if rule.ID == path[0] {
+			index = i
+		}
+	}
+	if index == -1 {
+		fakeNotFound(w)
+		return
+	}
+	rule := rules[index]
+
+	switch r.Method {
+	case "GET":
+		fakeJSON(w, http.StatusOK, api.GetSecurityGroupRule{Rules: *rule})
+	case "PUT":
+		var definition api.NewSecurityGroupRule
+		if !fakeDecode(w, r, &definition) {
+			return
+		}
+		rule.Action = definition.Action
+		rule.Direction = definition.Direction
+		rule.IPRange = definition.IPRange
+		rule.Protocol = definition.Protocol
+		rule.DestPortFrom = definition.DestPortFrom
+		fakeJSON(w, http.StatusOK, api.GetSecurityGroupRule{Rules: *rule})
+	case "DELETE":
+		f.rules[group.ID] = append(rules[:index], rules[index+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		fakeMethodNotAllowed(w)
+	}
+}
+
+func (f *fakeScalewayAPI) serveAccount(w http.ResponseWriter, r *http.Request, path []string) {
+	switch {
+	case len(path) == 2 && path[0] == "tokens" && path[1] == fakeToken:
+		fakeJSON(w, http.StatusOK, api.ScalewayTokensDefinition{
+			Token: api.ScalewayTokenDefinition{ID: fakeToken, UserID: fakeUserID},
+		})
+	case len(path) == 2 && path[0] == "users" && path[1] == fakeUserID:
+		if r.Method == "PATCH" {
+			var definition api.ScalewayUserPatchSSHKeyDefinition
+			if !fakeDecode(w, r, &definition) {
+				return
+			}
+			f.sshKeys = []api.ScalewayKeyDefinition{}
+			for _, key := range definition.SSHPublicKeys {
+				f.sshKeys = append(f.sshKeys, api.ScalewayKeyDefinition{
+					Key:         key.Key,
+					Fingerprint: fakeFingerprint(key.Key),
+				})
+			}
+		}
+		fakeJSON(w, http.StatusOK, api.ScalewayUsersDefinition{
+			User: api.ScalewayUserDefinition{
+				ID:            fakeUserID,
+				Email:         "terraform@example.com",
+				SSHPublicKeys: f.sshKeys,
+			},
+		})
+	default:
+		fakeNotFound(w)
+	}
+}
+
+func (f *fakeScalewayAPI) serveMarketplace(w http.ResponseWriter, r *http.Request, path []string) {
+	if len(path) == 1 && path[0] == "images" {
+		fakeJSON(w, http.StatusOK, api.MarketImages{Images: f.marketplace})
+		return
+	}
+	fakeNotFound(w)
+}
+
+// fakeFingerprint returns the fingerprint of an SSH public key the way the account API does.
+func fakeFingerprint(key string) string {
+	fields := strings.Fields(key)
+	if len(fields) < 2 {
+		return ""
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return ""
+	}
+	sum := md5.Sum(blob)
+	hex := []string{}
+	for _, b := range sum {
+		hex = append(hex, fmt.Sprintf("%02x", b))
+	}
+	return fmt.Sprintf("2048 %s (RSA)", strings.Join(hex, ":"))
+}
+
+func fakeUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func fakeNow() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000000+00:00")
+}
+
+// sortedKeys returns the keys of a map of API objects in a stable order.
+func sortedKeys(objects interface{}) []string {
+	keys := []string{}
+	switch objects := objects.(type) {
+	case map[string]*api.ScalewayServer:
+		for key := range objects {
+			keys = append(keys, key)
+		}
+	case map[string]*api.ScalewayVolume:
+		for key := range objects {
+			keys = append(keys, key)
+		}
+	case map[string]*api.ScalewaySnapshot:
+		for key := range objects {
+			keys = append(keys, key)
+		}
+	case map[string]*api.ScalewayImage:
+		for key := range objects {
+			keys = append(keys, key)
+		}
+	case map[string]*api.ScalewayBootscript:
+		for key := range objects {
+			keys = append(keys, key)
+		}
+	case map[string]*api.ScalewayIPDefinition:
+		for key := range objects {
+			keys = append(keys, key)
+		}
+	case map[string]*api.ScalewaySecurityGroups:
+		for key := range objects {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func fakeDecode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		fakeError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return false
+	}
+	return true
+}
+
+func fakeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func fakeError(w http.ResponseWriter, status int, kind, message string) {
+	fakeJSON(w, status, api.ScalewayAPIError{Type: kind, APIMessage: message})
+}
+
+func fakeNotFound(w http.ResponseWriter) {
+	fakeError(w, http.StatusNotFound, "unknown_resource", "resource not found")
+}
+
+func fakeMethodNotAllowed(w http.ResponseWriter) {
+	fakeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method not allowed")
+}
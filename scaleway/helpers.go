@@ -2,10 +2,15 @@ package scaleway
 
 import (
 	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/nicolai86/scaleway-sdk/api"
 )
 
@@ -19,6 +24,16 @@ func String(val string) *string {
 	return &val
 }
 
+// parseCreationDate parses the creation date returned by the Scaleway API.
+// Unparseable dates are treated as the zero time so they sort last.
+func parseCreationDate(date string) time.Time {
+	t, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
 func validateServerType(v interface{}, k string) (ws []string, errors []error) {
 	// only validate if we were able to fetch a list of commercial types
 	if len(commercialServerTypes) == 0 {
@@ -45,53 +60,408 @@ func validateVolumeType(v interface{}, k string) (ws []string, errors []error) {
 	return
 }
 
-// deleteRunningServer terminates the server and waits until it is removed.
-func deleteRunningServer(scaleway *api.ScalewayAPI, server *api.ScalewayServer) error {
-	err := scaleway.PostServerAction(server.Identifier, "terminate")
+// scalewayArchitectures are the architectures of Scaleway servers, images and bootscripts.
+var scalewayArchitectures = []string{"arm", "arm64", "x86_64"}
+
+func validateArchitecture(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	for _, arch := range scalewayArchitectures {
+		if value == arch {
+			return
+		}
+	}
+	errors = append(errors, fmt.Errorf("%q must be one of %q, got %q", k, scalewayArchitectures, value))
+	return
+}
 
+func validateBoolString(v interface{}, k string) (ws []string, errors []error) {
+	if value := v.(string); value != "true" && value != "false" {
+		errors = append(errors, fmt.Errorf("%q must be true or false, got %q", k, value))
+	}
+	return
+}
+
+func validateRegion(v interface{}, k string) (ws []string, errors []error) {
+	if !isScalewayRegion(v.(string)) {
+		errors = append(errors, fmt.Errorf("%q must be one of %q", k, scalewayRegions))
+	}
+	return
+}
+
+// regionSchema is the schema of the region argument shared by all regional
+// resources and data sources. It defaults to the region of the provider.
+func regionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ForceNew:     true,
+		ValidateFunc: validateRegion,
+		Description:  "the region of the resource, defaults to the region of the provider",
+	}
+}
+
+// importRegionalResource accepts import IDs of the form region/id next to plain IDs.
+func importRegionalResource(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if parts := strings.SplitN(d.Id(), "/", 2); len(parts) == 2 && isScalewayRegion(parts[0]) {
+		d.Set("region", parts[0])
+		d.SetId(parts[1])
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// commercialTypeArch returns the architecture of the servers of a commercial type,
+// e.g. arm for C1 and x86_64 for VC1S.
+func commercialTypeArch(commercialType string) string {
+	switch {
+	case commercialType == "C1":
+		return "arm"
+	case strings.HasPrefix(commercialType, "ARM64-"):
+		return "arm64"
+	default:
+		return "x86_64"
+	}
+}
+
+func validateVolumesOnDestroy(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value != "delete" && value != "detach" {
+		errors = append(errors, fmt.Errorf("%q must be one of delete or detach, got %q", k, value))
+	}
+	return
+}
+
+// serverStates maps the values of the state attribute of servers to the
+// states of the Scaleway API.
+var serverStates = map[string]string{
+	"running": "running",
+	"stopped": "stopped",
+	"standby": "stopped in place",
+}
+
+func validateServerState(v interface{}, k string) (ws []string, errors []error) {
+	state := v.(string)
+	if _, ok := serverStates[state]; !ok {
+		errors = append(errors, fmt.Errorf("%q must be one of running, stopped or standby, got %q", k, state))
+	}
+	return
+}
+
+// serverState returns the value of the state attribute for a Scaleway API state.
+func serverState(apiState string) string {
+	for state, s := range serverStates {
+		if s == apiState {
+			return state
+		}
+	}
+	return apiState
+}
+
+// setServerState powers the server on or off to bring it into the given state
+// (running, stopped or standby), waiting for pending transitions first. All
+// steps share the timeout.
+func setServerState(scaleway *api.ScalewayAPI, serverID, state string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	if err := waitForServerStates(scaleway, serverID, []string{"running", "stopped", "stopped in place"}, time.Until(deadline)); err != nil {
+		return err
+	}
+	server, err := scaleway.GetServer(serverID)
 	if err != nil {
-		if serr, ok := err.(api.ScalewayAPIError); ok {
-			if serr.StatusCode == 404 {
-				return nil
+		return err
+	}
+
+	target := serverStates[state]
+	if server.State == target {
+		return nil
+	}
+
+	var action string
+	switch state {
+	case "running":
+		action = "poweron"
+	case "stopped":
+		action = "poweroff"
+	case "standby":
+		// servers can only be put in standby while running
+		if server.State == "stopped" {
+			if err := setServerState(scaleway, serverID, "running", time.Until(deadline)); err != nil {
+				return err
+			}
+		}
+		action = "stop_in_place"
+	default:
+		return fmt.Errorf("Unknown server state %q", state)
+	}
+
+	log.Printf("[DEBUG] Changing state of server %q from %q to %q\n", serverID, server.State, target)
+	if err := scaleway.PostServerAction(serverID, action); err != nil {
+		return err
+	}
+	return waitForServerState(scaleway, serverID, target, time.Until(deadline))
+}
+
+func validateServerWaitFor(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value != "running" && value != "booted" && value != "ssh" {
+		errors = append(errors, fmt.Errorf("%q must be one of running, booted or ssh, got %q", k, value))
+	}
+	return
+}
+
+func validateDuration(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a duration, e.g. 5m: %s", k, err))
+	}
+	return
+}
+
+// waitForServerBooted waits until the kernel of the running server has booted.
+func waitForServerBooted(scaleway *api.ScalewayAPI, serverID string, timeout time.Duration) error {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"booting"},
+		Target:  []string{"booted"},
+		Refresh: func() (interface{}, string, error) {
+			s, err := scaleway.GetServer(serverID)
+			if err != nil {
+				return 42, "error", err
 			}
+			if s.State != "running" {
+				return 42, "error", fmt.Errorf("Server %q is %s while booting", serverID, s.State)
+			}
+			if s.StateDetail == "booted" {
+				return 42, "booted", nil
+			}
+			return 42, "booting", nil
+		},
+		Timeout:    timeout,
+		MinTimeout: stateRefreshInterval,
+		Delay:      stateRefreshInterval,
+	}
+	_, err := stateConf.WaitForState()
+	return err
+}
+
+// waitForTCP waits until a TCP connection to address can be opened, e.g. to
+// the SSH port of a server.
+func waitForTCP(address string, timeout time.Duration) error {
+	return resource.Retry(timeout, func() *resource.RetryError {
+		conn, err := net.DialTimeout("tcp", address, 10*time.Second)
+		if err != nil {
+			log.Printf("[DEBUG] Waiting for %s to accept connections: %s\n", address, err)
+			return resource.RetryableError(fmt.Errorf("Waiting for %s to accept connections: %s", address, err))
 		}
+		conn.Close()
+		return nil
+	})
+}
 
+// rebootServer reboots the server if it is running, and waits until it is running again.
+func rebootServer(scaleway *api.ScalewayAPI, serverID string, timeout time.Duration) error {
+	server, err := scaleway.GetServer(serverID)
+	if err != nil {
 		return err
 	}
+	if server.State != "running" {
+		return nil
+	}
 
-	return waitForServerState(scaleway, server.Identifier, "stopped")
+	log.Printf("[DEBUG] Rebooting server %q\n", serverID)
+	if err := scaleway.PostServerAction(serverID, "reboot"); err != nil {
+		return err
+	}
+	return waitForServerState(scaleway, serverID, "running", timeout)
 }
 
-// deleteStoppedServer needs to cleanup attached root volumes. this is not done
-// automatically by Scaleway
-func deleteStoppedServer(scaleway *api.ScalewayAPI, server *api.ScalewayServer) error {
+// withServerStopped powers off the server if needed, calls fn and brings the
+// server back into its previous state. All steps share the timeout, fn is
+// passed the time left.
+func withServerStopped(scaleway *api.ScalewayAPI, serverID string, timeout time.Duration, fn func(timeout time.Duration) error) error {
+	deadline := time.Now().Add(timeout)
+	if err := waitForServerStates(scaleway, serverID, []string{"running", "stopped", "stopped in place"}, time.Until(deadline)); err != nil {
+		return err
+	}
+	server, err := scaleway.GetServer(serverID)
+	if err != nil {
+		return err
+	}
+
+	if err := setServerState(scaleway, serverID, "stopped", time.Until(deadline)); err != nil {
+		return err
+	}
+
+	if err := fn(time.Until(deadline)); err != nil {
+		return err
+	}
+
+	return setServerState(scaleway, serverID, serverState(server.State), time.Until(deadline))
+}
+
+// sortedServerVolumes returns the volumes of the server ordered by their index,
+// starting with the root volume.
+func sortedServerVolumes(server *api.ScalewayServer) []api.ScalewayVolume {
+	indexes := []int{}
+	for index := range server.Volumes {
+		if i, err := strconv.Atoi(index); err == nil {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.Ints(indexes)
+
+	volumes := []api.ScalewayVolume{}
+	for _, i := range indexes {
+		volumes = append(volumes, server.Volumes[strconv.Itoa(i)])
+	}
+	return volumes
+}
+
+// patchServerVolumes replaces the volumes of the server, starting with the root
+// volume. The server is powered off while its volumes are changed.
+func patchServerVolumes(scaleway *api.ScalewayAPI, serverID string, volumes []api.ScalewayVolume, timeout time.Duration) error {
+	// the API request requires most volume attributes to be unset to succeed
+	req := make(map[string]api.ScalewayVolume)
+	for i, volume := range volumes {
+		req[strconv.Itoa(i)] = api.ScalewayVolume{
+			Identifier: volume.Identifier,
+			Name:       volume.Name,
+		}
+	}
+
+	return withServerStopped(scaleway, serverID, timeout, func(timeout time.Duration) error {
+		// the server and volumes may still be settling after a state change,
+		// other errors are not retried
+		if timeout > volumeDetachTimeout {
+			timeout = volumeDetachTimeout
+		}
+		return resource.Retry(timeout, func() *resource.RetryError {
+			err := scaleway.PatchServer(serverID, api.ScalewayServerPatchDefinition{
+				Volumes: &req,
+			})
+			if err == nil {
+				return nil
+			}
+
+			if serr, ok := err.(api.ScalewayAPIError); ok {
+				log.Printf("[DEBUG] Error patching server: %q\n", serr.APIMessage)
+
+				if serr.StatusCode == 400 && serverVolumesBusy(scaleway, serverID, volumes) {
+					return resource.RetryableError(fmt.Errorf("Waiting for server update to succeed: %q", serr.APIMessage))
+				}
+			}
+
+			return resource.NonRetryableError(err)
+		})
+	})
+}
+
+// serverVolumesBusy returns whether the server is not stopped yet, or one of
+// the volumes is still attached to another server.
+func serverVolumesBusy(scaleway *api.ScalewayAPI, serverID string, volumes []api.ScalewayVolume) bool {
+	server, err := scaleway.GetServer(serverID)
+	if err != nil {
+		return false
+	}
+	if server.State != "stopped" {
+		return true
+	}
+	for _, v := range volumes {
+		volume, err := scaleway.GetVolume(v.Identifier)
+		if err == nil && volume.Server != nil && volume.Server.Identifier != serverID {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteServer deletes the server and waits until it is removed. The root
+// volume and the additional volumes are deleted as well unless deleteRootVolume
+// and deleteVolumes are false, in which case they are detached from the server.
+func deleteServer(scaleway *api.ScalewayAPI, server *api.ScalewayServer, deleteRootVolume, deleteVolumes bool, timeout time.Duration) error {
+	if server.State != "stopped" && deleteRootVolume && deleteVolumes {
+		// terminating the server deletes its volumes
+		return terminateServer(scaleway, server, timeout)
+	}
+
+	deadline := time.Now().Add(timeout)
+	if err := setServerState(scaleway, server.Identifier, "stopped", time.Until(deadline)); err != nil {
+		return err
+	}
+
+	volumes := sortedServerVolumes(server)
+	if len(volumes) > 1 && !deleteVolumes {
+		log.Printf("[DEBUG] Detaching volumes of server %q\n", server.Identifier)
+		if err := patchServerVolumes(scaleway, server.Identifier, volumes[:1], time.Until(deadline)); err != nil {
+			return err
+		}
+	}
+
 	if err := scaleway.DeleteServer(server.Identifier); err != nil {
 		return err
 	}
 
-	if rootVolume, ok := server.Volumes["0"]; ok {
-		if err := scaleway.DeleteVolume(rootVolume.Identifier); err != nil {
+	// volumes of deleted servers are kept by Scaleway
+	for i, volume := range volumes {
+		if (i == 0 && !deleteRootVolume) || (i > 0 && !deleteVolumes) {
+			continue
+		}
+		if err := scaleway.DeleteVolume(volume.Identifier); err != nil {
+			if serr, ok := err.(api.ScalewayAPIError); ok && serr.StatusCode == 404 {
+				continue
+			}
 			return err
 		}
 	}
 	return nil
 }
 
+// terminateServer terminates the server and waits until it is removed.
+func terminateServer(scaleway *api.ScalewayAPI, server *api.ScalewayServer, timeout time.Duration) error {
+	err := scaleway.PostServerAction(server.Identifier, "terminate")
+
+	if err != nil {
+		if serr, ok := err.(api.ScalewayAPIError); ok {
+			if serr.StatusCode == 404 {
+				return nil
+			}
+		}
+
+		return err
+	}
+
+	return waitForServerState(scaleway, server.Identifier, "stopped", timeout)
+}
+
 // NOTE copied from github.com/scaleway/scaleway-cli/pkg/api/helpers.go
 // the helpers.go file pulls in quite a lot dependencies, and they're just convenience wrappers anyway
 
-var allStates = []string{"starting", "running", "stopping", "stopped"}
+// defaultTimeout is used for operations which wait on the Scaleway API
+// unless the resource configures its own timeouts.
+const defaultTimeout = 60 * time.Minute
+
+// stateRefreshInterval is the delay between two polls of the Scaleway API
+// while waiting for a state change.
+var stateRefreshInterval = 5 * time.Second
+
+var allStates = []string{"starting", "running", "stopping", "stopped", "stopped in place"}
 
-func waitForServerState(scaleway *api.ScalewayAPI, serverID, targetState string) error {
+func waitForServerState(scaleway *api.ScalewayAPI, serverID, targetState string, timeout time.Duration) error {
+	return waitForServerStates(scaleway, serverID, []string{targetState}, timeout)
+}
+
+// waitForServerStates waits until the server reaches one of the target states.
+func waitForServerStates(scaleway *api.ScalewayAPI, serverID string, targetStates []string, timeout time.Duration) error {
 	pending := []string{}
 	for _, state := range allStates {
-		if state != targetState {
+		target := false
+		for _, targetState := range targetStates {
+			target = target || state == targetState
+		}
+		if !target {
 			pending = append(pending, state)
 		}
 	}
 	stateConf := &resource.StateChangeConf{
 		Pending: pending,
-		Target:  []string{targetState},
+		Target:  targetStates,
 		Refresh: func() (interface{}, string, error) {
 			s, err := scaleway.GetServer(serverID)
 
@@ -110,9 +480,36 @@ func waitForServerState(scaleway *api.ScalewayAPI, serverID, targetState string)
 			}
 			return 42, "error", err
 		},
-		Timeout:    60 * time.Minute,
-		MinTimeout: 5 * time.Second,
-		Delay:      5 * time.Second,
+		Timeout:    timeout,
+		MinTimeout: stateRefreshInterval,
+		Delay:      stateRefreshInterval,
+	}
+	_, err := stateConf.WaitForState()
+	return err
+}
+
+var allSnapshotStates = []string{"snapshotting", "available"}
+
+func waitForSnapshotState(scaleway *api.ScalewayAPI, snapshotID, targetState string, timeout time.Duration) error {
+	pending := []string{}
+	for _, state := range allSnapshotStates {
+		if state != targetState {
+			pending = append(pending, state)
+		}
+	}
+	stateConf := &resource.StateChangeConf{
+		Pending: pending,
+		Target:  []string{targetState},
+		Refresh: func() (interface{}, string, error) {
+			s, err := scaleway.GetSnapshot(snapshotID)
+			if err != nil {
+				return 42, "error", err
+			}
+			return 42, s.State, nil
+		},
+		Timeout:    timeout,
+		MinTimeout: stateRefreshInterval,
+		Delay:      stateRefreshInterval,
 	}
 	_, err := stateConf.WaitForState()
 	return err
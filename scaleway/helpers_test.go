@@ -0,0 +1,108 @@
+package scaleway
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nicolai86/scaleway-sdk/api"
+)
+
+func TestWaitForTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer listener.Close()
+
+	if err := waitForTCP(listener.Addr().String(), time.Second); err != nil {
+		t.Fatalf("Expected %s to accept connections: %s", listener.Addr(), err)
+	}
+}
+
+func TestWaitForTCP_Timeout(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	if err := waitForTCP(address, time.Second); err == nil {
+		t.Fatalf("Expected waiting for %s to time out", address)
+	}
+}
+
+func TestValidateServerWaitFor(t *testing.T) {
+	for _, value := range []string{"running", "booted", "ssh"} {
+		if _, errors := validateServerWaitFor(value, "wait_for"); len(errors) != 0 {
+			t.Errorf("Expected %q to be valid: %v", value, errors)
+		}
+	}
+	if _, errors := validateServerWaitFor("started", "wait_for"); len(errors) == 0 {
+		t.Errorf("Expected %q to be invalid", "started")
+	}
+}
+
+func TestValidateArchitecture(t *testing.T) {
+	for _, value := range []string{"arm", "arm64", "x86_64"} {
+		if _, errors := validateArchitecture(value, "architecture"); len(errors) != 0 {
+			t.Errorf("Expected %q to be valid: %v", value, errors)
+		}
+	}
+	for _, value := range []string{"", "amd64", "ARM"} {
+		if _, errors := validateArchitecture(value, "architecture"); len(errors) == 0 {
+			t.Errorf("Expected %q to be invalid", value)
+		}
+	}
+}
+
+func TestDeleteServer_DetachError(t *testing.T) {
+	fake := newFakeScalewayAPI()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if r.Method == "PATCH" && len(path) == 3 && path[1] == "servers" {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", "volumes can not be changed")
+			return
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	client := testProviderClient(t, server.URL+"/compute")
+
+	defer func(interval time.Duration) { stateRefreshInterval = interval }(stateRefreshInterval)
+	stateRefreshInterval = 10 * time.Millisecond
+
+	volumeID, err := client.scaleway.PostVolume(api.ScalewayVolumeDefinition{
+		Name: "test",
+		Size: 2 * gb,
+		Type: "l_ssd",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	serverID, err := client.scaleway.PostServer(api.ScalewayServerDefinition{
+		Name:           "test",
+		Image:          String(armImageIdentifier),
+		CommercialType: "C1",
+		Volumes:        map[string]string{"1": volumeID},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	s, err := client.scaleway.GetServer(serverID)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	start := time.Now()
+	if err := deleteServer(client.scaleway, s, true, false, time.Hour); err == nil {
+		t.Fatal("Expected detaching the volumes to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Expected detaching the volumes to fail right away, took %s", elapsed)
+	}
+}
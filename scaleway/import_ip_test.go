@@ -26,3 +26,25 @@ func TestAccScalewayIP_importBasic(t *testing.T) {
 		},
 	})
 }
+
+func TestAccScalewayIP_importRegion(t *testing.T) {
+	resourceName := "scaleway_ip.ams"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScalewayIPDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewayIPConfig_Region,
+			},
+
+			resource.TestStep{
+				ResourceName:        resourceName,
+				ImportState:         true,
+				ImportStateIdPrefix: "ams1/",
+				ImportStateVerify:   true,
+			},
+		},
+	})
+}
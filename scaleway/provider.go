@@ -1,16 +1,24 @@
 package scaleway
 
 import (
-	"sync"
+	"fmt"
 
+	"github.com/hashicorp/terraform/helper/mutexkv"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
 
-var mu = sync.Mutex{}
+// scalewayMutexKV serializes modifications of Scaleway objects which can not
+// be changed concurrently, keyed by the ID of the contended object.
+// e.g. servers are locked while being power cycled to attach volumes.
+var scalewayMutexKV = mutexkv.NewMutexKV()
 
 // Provider returns a terraform.ResourceProvider.
 func Provider() terraform.ResourceProvider {
+	return &scalewayProvider{provider()}
+}
+
+func provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"access_key": &schema.Schema{
@@ -22,7 +30,7 @@ func Provider() terraform.ResourceProvider {
 			},
 			"token": &schema.Schema{
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
 				DefaultFunc: schema.MultiEnvDefaultFunc([]string{
 					"SCALEWAY_TOKEN",
 					"SCALEWAY_ACCESS_KEY",
@@ -31,23 +39,75 @@ func Provider() terraform.ResourceProvider {
 			},
 			"organization": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("SCALEWAY_ORGANIZATION", nil),
 				Description: "The Organization ID (a.k.a. 'access key') for Scaleway API operations.",
 			},
 			"region": &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("SCALEWAY_REGION", "par1"),
+				DefaultFunc: schema.EnvDefaultFunc("SCALEWAY_REGION", nil),
 				Description: "The Scaleway API region to use.",
 			},
+			"config_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("SCALEWAY_CONFIG_FILE", nil),
+				Description: "The path of the scaleway CLI configuration file to read credentials from, defaults to ~/.scwrc.",
+			},
+			"max_retries": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("SCALEWAY_MAX_RETRIES", 3),
+				Description: "The maximum number of times a request failing with a transient error is retried.",
+				ValidateFunc: func(v interface{}, k string) (ws []string, errors []error) {
+					if v.(int) < 0 {
+						errors = append(errors, fmt.Errorf("%q must not be negative, got %d", k, v.(int)))
+					}
+					return
+				},
+			},
+			"endpoints": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Custom endpoints of the Scaleway APIs, e.g. to use a local stand-in of the API.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"compute": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The endpoint of the compute API, used for all regions.",
+						},
+						"account": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The endpoint of the account API.",
+						},
+						"marketplace": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The endpoint of the marketplace API.",
+						},
+						"availability": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The endpoint of the availability API, used for all regions.",
+						},
+					},
+				},
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
 			"scaleway_server":              resourceScalewayServer(),
+			"scaleway_image":               resourceScalewayImage(),
 			"scaleway_ip":                  resourceScalewayIP(),
 			"scaleway_security_group":      resourceScalewaySecurityGroup(),
 			"scaleway_security_group_rule": resourceScalewaySecurityGroupRule(),
+			"scaleway_snapshot":            resourceScalewaySnapshot(),
+			"scaleway_ssh_key":             resourceScalewaySSHKey(),
+			"scaleway_user_data":           resourceScalewayUserData(),
 			"scaleway_volume":              resourceScalewayVolume(),
 			"scaleway_volume_attachment":   resourceScalewayVolumeAttachment(),
 		},
@@ -55,27 +115,92 @@ func Provider() terraform.ResourceProvider {
 		DataSourcesMap: map[string]*schema.Resource{
 			"scaleway_bootscript": dataSourceScalewayBootscript(),
 			"scaleway_image":      dataSourceScalewayImage(),
+			"scaleway_server":     dataSourceScalewayServer(),
+			"scaleway_snapshot":   dataSourceScalewaySnapshot(),
 		},
 
 		ConfigureFunc: providerConfigure,
 	}
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	apiKey := ""
-	if v, ok := d.Get("token").(string); ok {
-		apiKey = v
-	} else {
-		if v, ok := d.Get("access_key").(string); ok {
-			apiKey = v
+// scalewayProvider extends the diff of schema.Provider, which can not check
+// attributes against their old values.
+type scalewayProvider struct {
+	*schema.Provider
+}
+
+func (p *scalewayProvider) Diff(info *terraform.InstanceInfo, s *terraform.InstanceState, c *terraform.ResourceConfig) (*terraform.InstanceDiff, error) {
+	diff, err := p.Provider.Diff(info, s, c)
+	if err != nil || diff == nil {
+		return diff, err
+	}
+
+	if info.Type == "scaleway_server" {
+		if diff, err = diffServerVolumes(s, diff); err != nil {
+			return nil, err
 		}
+		return diffServerType(diff)
+	}
+	return diff, nil
+}
+
+func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	config, err := providerConfig(d)
+	if err != nil {
+		return nil, err
+	}
+	return config.Client()
+}
+
+// providerConfig builds the configuration of the provider. Credentials and region
+// which are neither set in the provider block nor in the environment are read
+// from the scaleway CLI configuration file.
+func providerConfig(d *schema.ResourceData) (*Config, error) {
+	apiKey := d.Get("token").(string)
+	if apiKey == "" {
+		apiKey = d.Get("access_key").(string)
 	}
 
-	config := Config{
+	config := &Config{
 		Organization: d.Get("organization").(string),
 		APIKey:       apiKey,
 		Region:       d.Get("region").(string),
+		MaxRetries:   d.Get("max_retries").(int),
 	}
 
-	return config.Client()
+	if config.Organization == "" || config.APIKey == "" || config.Region == "" {
+		scwrc, err := readScalewayConfigFile(d.Get("config_file").(string))
+		if err != nil {
+			return nil, err
+		}
+		if config.Organization == "" {
+			config.Organization = scwrc.Organization
+		}
+		if config.APIKey == "" {
+			config.APIKey = scwrc.Token
+		}
+		if config.Region == "" {
+			config.Region = scwrc.Region
+		}
+	}
+	if config.Region == "" {
+		config.Region = "par1"
+	}
+	if config.Organization == "" {
+		return nil, fmt.Errorf("organization must be set in the provider, through SCALEWAY_ORGANIZATION or in the scaleway configuration file")
+	}
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("token must be set in the provider, through SCALEWAY_TOKEN or in the scaleway configuration file")
+	}
+
+	if endpoints, ok := d.GetOk("endpoints"); ok {
+		if endpoint, ok := endpoints.([]interface{})[0].(map[string]interface{}); ok {
+			config.ComputeEndpoint = endpoint["compute"].(string)
+			config.AccountEndpoint = endpoint["account"].(string)
+			config.MarketplaceEndpoint = endpoint["marketplace"].(string)
+			config.AvailabilityEndpoint = endpoint["availability"].(string)
+		}
+	}
+
+	return config, nil
 }
@@ -0,0 +1,209 @@
+package scaleway
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/nicolai86/scaleway-sdk/api"
+)
+
+// concurrencyTracker records the maximum number of concurrent requests per key.
+type concurrencyTracker struct {
+	lock     sync.Mutex
+	inflight map[string]int
+	max      map[string]int
+}
+
+func newConcurrencyTracker() *concurrencyTracker {
+	return &concurrencyTracker{
+		inflight: make(map[string]int),
+		max:      make(map[string]int),
+	}
+}
+
+func (c *concurrencyTracker) enter(keys ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, key := range keys {
+		c.inflight[key]++
+		if c.inflight[key] > c.max[key] {
+			c.max[key] = c.inflight[key]
+		}
+	}
+}
+
+func (c *concurrencyTracker) leave(keys ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, key := range keys {
+		c.inflight[key]--
+	}
+}
+
+func (c *concurrencyTracker) maxConcurrency(key string) int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.max[key]
+}
+
+// testMutexClient returns a client talking to the given fake compute and account APIs.
+func testMutexClient(t *testing.T, compute, account http.Handler) (*Client, func()) {
+	computeServer := httptest.NewServer(compute)
+	accountServer := httptest.NewServer(account)
+
+	scaleway, err := api.New("organization", "token", "par1",
+		api.WithComputeAPI(computeServer.URL),
+		api.WithAccountAPI(accountServer.URL),
+	)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return &Client{scaleway: scaleway, regions: map[string]*api.ScalewayAPI{"par1": scaleway}}, func() {
+		computeServer.Close()
+		accountServer.Close()
+	}
+}
+
+func TestScalewayMutexKV_SecurityGroupRules(t *testing.T) {
+	tracker := newConcurrencyTracker()
+
+	compute := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// /security_groups/<group>/rules[/<rule>]
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) < 3 || parts[0] != "security_groups" || parts[2] != "rules" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		group := parts[1]
+
+		switch r.Method {
+		case "HEAD":
+			w.WriteHeader(http.StatusOK)
+		case "POST":
+			tracker.enter("all", group)
+			time.Sleep(50 * time.Millisecond)
+			tracker.leave("all", group)
+
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"rule": api.SecurityGroupRule{ID: fmt.Sprintf("%s-rule", group)},
+			})
+		case "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"rule": api.SecurityGroupRule{ID: parts[3]},
+			})
+		}
+	})
+
+	client, cleanup := testMutexClient(t, compute, http.NotFoundHandler())
+	defer cleanup()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		d := schema.TestResourceDataRaw(t, resourceScalewaySecurityGroupRule().Schema, map[string]interface{}{
+			"security_group": fmt.Sprintf("group-%d", i%3),
+			"action":         "accept",
+			"direction":      "inbound",
+			"ip_range":       "0.0.0.0/0",
+			"protocol":       "TCP",
+			"port":           22,
+		})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := resourceScalewaySecurityGroupRuleCreate(d, client); err != nil {
+				t.Errorf("err: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if max := tracker.maxConcurrency("all"); max < 2 {
+		t.Errorf("expected rules of different security groups to be created in parallel, got %d concurrent requests", max)
+	}
+	for i := 0; i < 3; i++ {
+		group := fmt.Sprintf("group-%d", i)
+		if max := tracker.maxConcurrency(group); max != 1 {
+			t.Errorf("expected rules of %s to be created serially, got %d concurrent requests", group, max)
+		}
+	}
+}
+
+func TestScalewayMutexKV_SSHKeys(t *testing.T) {
+	tracker := newConcurrencyTracker()
+
+	var lock sync.Mutex
+	keys := []api.ScalewayKeyDefinition{}
+
+	account := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "HEAD":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/tokens/token":
+			json.NewEncoder(w).Encode(api.ScalewayTokensDefinition{
+				Token: api.ScalewayTokenDefinition{UserID: "user"},
+			})
+		case r.URL.Path == "/users/user" && r.Method == "GET":
+			lock.Lock()
+			defer lock.Unlock()
+			json.NewEncoder(w).Encode(api.ScalewayUsersDefinition{
+				User: api.ScalewayUserDefinition{ID: "user", SSHPublicKeys: keys},
+			})
+		case r.URL.Path == "/users/user" && r.Method == "PATCH":
+			tracker.enter("patch")
+			defer tracker.leave("patch")
+
+			var req api.ScalewayUserPatchSSHKeyDefinition
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+
+			lock.Lock()
+			keys = []api.ScalewayKeyDefinition{}
+			for _, key := range req.SSHPublicKeys {
+				keys = append(keys, api.ScalewayKeyDefinition{Key: key.Key, Fingerprint: "fp " + key.Key})
+			}
+			lock.Unlock()
+			json.NewEncoder(w).Encode(api.ScalewayUsersDefinition{})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	client, cleanup := testMutexClient(t, http.NotFoundHandler(), account)
+	defer cleanup()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		d := schema.TestResourceDataRaw(t, resourceScalewaySSHKey().Schema, map[string]interface{}{
+			"key": fmt.Sprintf("ssh-rsa key-%d", i),
+		})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := resourceScalewaySSHKeyCreate(d, client); err != nil {
+				t.Errorf("err: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if max := tracker.maxConcurrency("patch"); max != 1 {
+		t.Errorf("expected SSH key patches to be serialized, got %d concurrent requests", max)
+	}
+	if len(keys) != 5 {
+		t.Errorf("expected 5 SSH keys, got %d: %v", len(keys), keys)
+	}
+}
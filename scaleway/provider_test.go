@@ -1,9 +1,13 @@
 package scaleway
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -12,14 +16,18 @@ var testAccProviders map[string]terraform.ResourceProvider
 var testAccProvider *schema.Provider
 
 func init() {
-	testAccProvider = Provider().(*schema.Provider)
+	testAccProvider = provider()
 	testAccProviders = map[string]terraform.ResourceProvider{
-		"scaleway": testAccProvider,
+		"scaleway": &scalewayProvider{testAccProvider},
+	}
+
+	if os.Getenv("SCALEWAY_FAKE_API") != "" {
+		testAccUseFakeAPI(testAccProvider)
 	}
 }
 
 func TestProvider(t *testing.T) {
-	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
+	if err := provider().InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
 	}
 }
@@ -28,6 +36,127 @@ func TestProvider_impl(t *testing.T) {
 	var _ terraform.ResourceProvider = Provider()
 }
 
+func TestProvider_Endpoints(t *testing.T) {
+	requests := map[string]int{}
+	newComputeAPI := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests[name]++
+			w.Write([]byte(`{"ips": []}`))
+		}))
+	}
+	first, second := newComputeAPI("first"), newComputeAPI("second")
+	defer first.Close()
+	defer second.Close()
+
+	firstClient := testProviderClient(t, first.URL)
+	secondClient := testProviderClient(t, second.URL)
+	requests["first"], requests["second"] = 0, 0
+
+	if _, err := firstClient.scaleway.GetIPS(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if requests["first"] == 0 || requests["second"] != 0 {
+		t.Errorf("expected requests to the first endpoint only, got %v", requests)
+	}
+
+	requests["first"], requests["second"] = 0, 0
+	if _, err := secondClient.scaleway.GetIPS(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if requests["first"] != 0 || requests["second"] == 0 {
+		t.Errorf("expected requests to the second endpoint only, got %v", requests)
+	}
+}
+
+func TestProvider_ConfigFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "scwrc")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`{"organization": "file-organization", "token": "file-token", "region": "ams1"}`)
+	file.Close()
+
+	for _, name := range []string{"SCALEWAY_ORGANIZATION", "SCALEWAY_TOKEN", "SCALEWAY_ACCESS_KEY", "SCALEWAY_REGION"} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Unsetenv(name)
+	}
+
+	cases := []struct {
+		env      map[string]string
+		raw      map[string]interface{}
+		expected Config
+	}{
+		{
+			raw:      map[string]interface{}{},
+			expected: Config{Organization: "file-organization", APIKey: "file-token", Region: "ams1"},
+		},
+		{
+			env:      map[string]string{"SCALEWAY_ORGANIZATION": "env-organization", "SCALEWAY_REGION": "par1"},
+			raw:      map[string]interface{}{},
+			expected: Config{Organization: "env-organization", APIKey: "file-token", Region: "par1"},
+		},
+		{
+			env:      map[string]string{"SCALEWAY_ORGANIZATION": "env-organization", "SCALEWAY_TOKEN": "env-token"},
+			raw:      map[string]interface{}{"token": "token"},
+			expected: Config{Organization: "env-organization", APIKey: "token", Region: "ams1"},
+		},
+	}
+
+	for i, c := range cases {
+		for name, value := range c.env {
+			os.Setenv(name, value)
+		}
+		c.raw["config_file"] = file.Name()
+
+		d := schema.TestResourceDataRaw(t, provider().Schema, c.raw)
+		config, err := providerConfig(d)
+		if err != nil {
+			t.Fatalf("%d: err: %s", i, err)
+		}
+		if config.Organization != c.expected.Organization || config.APIKey != c.expected.APIKey || config.Region != c.expected.Region {
+			t.Errorf("%d: expected %+v, got %+v", i, c.expected, *config)
+		}
+
+		for name := range c.env {
+			os.Unsetenv(name)
+		}
+	}
+}
+
+func TestProvider_ConfigFileMissing(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, provider().Schema, map[string]interface{}{
+		"organization": "organization",
+		"config_file":  "/nonexistent/scwrc",
+	})
+	if _, err := providerConfig(d); err == nil {
+		t.Fatal("expected an error reading a missing configuration file")
+	}
+}
+
+// testProviderClient configures a provider using the given compute and availability endpoint.
+func testProviderClient(t *testing.T, endpoint string) *Client {
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"organization": "organization",
+		"token":        "token",
+		"endpoints": []map[string]interface{}{
+			{
+				"compute":      endpoint,
+				"availability": endpoint,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	provider := provider()
+	if err := provider.Configure(terraform.NewResourceConfig(raw)); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return provider.Meta().(*Client)
+}
+
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("SCALEWAY_ORGANIZATION"); v == "" {
 		t.Fatal("SCALEWAY_ORGANIZATION must be set for acceptance tests")
@@ -38,3 +167,134 @@ func testAccPreCheck(t *testing.T) {
 		t.Fatal("SCALEWAY_TOKEN must be set for acceptance tests")
 	}
 }
+
+func TestProvider_NegativeMaxRetries(t *testing.T) {
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"max_retries": -1,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	_, errors := Provider().Validate(terraform.NewResourceConfig(raw))
+	if len(errors) == 0 {
+		t.Fatal("Expected a negative max_retries to be rejected")
+	}
+}
+
+func TestProvider_DiffServerType(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "server",
+		Attributes: map[string]string{
+			"id":     "server",
+			"name":   "test",
+			"image":  armImageIdentifier,
+			"type":   "C1",
+			"region": "par1",
+		},
+	}
+	info := &terraform.InstanceInfo{Type: "scaleway_server"}
+
+	cases := []struct {
+		to, image, region string
+		err               bool
+		requiresNew       bool
+	}{
+		{"C1", armImageIdentifier, "par1", false, false},
+		{"C1", "other-image", "par1", false, true},
+		{"C1", armImageIdentifier, "ams1", false, true},
+		{"VC1S", armImageIdentifier, "par1", true, false},
+		{"VC1S", armImageIdentifier, "ams1", true, false},
+		{"VC1S", "other-image", "par1", false, true},
+	}
+	for _, c := range cases {
+		raw, err := config.NewRawConfig(map[string]interface{}{
+			"name":   "test",
+			"image":  c.image,
+			"type":   c.to,
+			"region": c.region,
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		diff, err := Provider().Diff(info, state, terraform.NewResourceConfig(raw))
+		if (err != nil) != c.err {
+			t.Errorf("Expected changing type to %s with image %s to fail: %t, got %v", c.to, c.image, c.err, err)
+			continue
+		}
+		if requiresNew := diff != nil && diff.RequiresNew(); requiresNew != c.requiresNew {
+			t.Errorf("Expected changing type to %s with image %s to replace the server: %t, got %t", c.to, c.image, c.requiresNew, requiresNew)
+		}
+		if c.requiresNew && c.to != "C1" && !diff.Attributes["type"].RequiresNew {
+			t.Errorf("Expected changing type to %s to force a new server", c.to)
+		}
+	}
+}
+
+func TestProvider_DiffServerVolumes(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "server",
+		Attributes: map[string]string{
+			"id":                  "server",
+			"name":                "test",
+			"image":               armImageIdentifier,
+			"type":                "C1",
+			"state_detail":        "booted",
+			"volume.#":            "3",
+			"volume.0.size_in_gb": "20",
+			"volume.0.type":       "l_ssd",
+			"volume.0.volume_id":  "volume-20",
+			"volume.1.size_in_gb": "0",
+			"volume.1.type":       "l_ssd",
+			"volume.1.volume_id":  "",
+			"volume.2.size_in_gb": "30",
+			"volume.2.type":       "l_ssd",
+			"volume.2.volume_id":  "volume-30",
+		},
+	}
+	info := &terraform.InstanceInfo{Type: "scaleway_server"}
+
+	cases := []struct {
+		sizes   []int
+		err     bool
+		changed bool
+	}{
+		{[]int{20, 0, 30}, false, false},
+		{[]int{20, 30}, false, true},
+		{[]int{20, 10, 30}, false, true},
+		{[]int{20, 0, 30, 40}, false, true},
+		{[]int{20, 0, 40}, true, false},
+		{[]int{40, 0, 30}, true, false},
+	}
+	for _, c := range cases {
+		volumes := []interface{}{}
+		for _, size := range c.sizes {
+			volumes = append(volumes, map[string]interface{}{
+				"size_in_gb": size,
+				"type":       "l_ssd",
+			})
+		}
+		raw, err := config.NewRawConfig(map[string]interface{}{
+			"name":   "test",
+			"image":  armImageIdentifier,
+			"type":   "C1",
+			"volume": volumes,
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		diff, err := Provider().Diff(info, state, terraform.NewResourceConfig(raw))
+		if (err != nil) != c.err {
+			t.Errorf("Expected changing volumes to %v to fail: %t, got %v", c.sizes, c.err, err)
+			continue
+		}
+		if diff != nil && diff.RequiresNew() {
+			t.Errorf("Expected changing volumes to %v not to replace the server", c.sizes)
+		}
+		if reboot := diff != nil && diff.Attributes["state_detail"] != nil && diff.Attributes["state_detail"].NewComputed; reboot != c.changed {
+			t.Errorf("Expected changing volumes to %v to show a reboot: %t, got %t", c.sizes, c.changed, reboot)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package scaleway
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/nicolai86/scaleway-sdk/api"
+)
+
+func resourceScalewayImage() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceScalewayImageCreate,
+		Read:   resourceScalewayImageRead,
+		Delete: resourceScalewayImageDelete,
+		Importer: &schema.ResourceImporter{
+			State: importRegionalResource,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": regionSchema(),
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "the name of the image",
+			},
+			"snapshot": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "the snapshot used as root volume of the image",
+			},
+			"architecture": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "the architecture of the image",
+				ValidateFunc: validateArchitecture,
+			},
+			"bootscript": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "the default bootscript of the image",
+			},
+			"organization": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "organization owning the image",
+			},
+			"public": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "indication if the image is public",
+			},
+			"creation_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "date when the image was created",
+			},
+		},
+	}
+}
+
+func resourceScalewayImageCreate(d *schema.ResourceData, m interface{}) error {
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+
+	imageID, err := scaleway.PostImage(
+		d.Get("snapshot").(string),
+		d.Get("name").(string),
+		d.Get("bootscript").(string),
+		d.Get("architecture").(string),
+	)
+	if err != nil {
+		return fmt.Errorf("Error creating image: %q", err)
+	}
+	d.SetId(imageID)
+	return resourceScalewayImageRead(d, m)
+}
+
+func resourceScalewayImageRead(d *schema.ResourceData, m interface{}) error {
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	d.Set("region", scaleway.Region)
+	image, err := scaleway.GetImage(d.Id())
+	if err != nil {
+		if serr, ok := err.(api.ScalewayAPIError); ok {
+			log.Printf("[DEBUG] Error reading image: %q\n", serr.APIMessage)
+
+			if serr.StatusCode == 404 {
+				d.SetId("")
+				return nil
+			}
+		}
+
+		return err
+	}
+
+	d.Set("name", image.Name)
+	d.Set("architecture", image.Arch)
+	// the root volume of an image references the snapshot it was built from
+	if image.RootVolume.Identifier != "" {
+		d.Set("snapshot", image.RootVolume.Identifier)
+	}
+	d.Set("organization", image.Organization)
+	d.Set("public", image.Public)
+	d.Set("creation_date", image.CreationDate)
+	d.Set("bootscript", "")
+	if image.DefaultBootscript != nil {
+		d.Set("bootscript", image.DefaultBootscript.Identifier)
+	}
+	return nil
+}
+
+func resourceScalewayImageDelete(d *schema.ResourceData, m interface{}) error {
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+
+	err = scaleway.DeleteImage(d.Id())
+	if err != nil {
+		if serr, ok := err.(api.ScalewayAPIError); ok {
+			if serr.StatusCode == 404 {
+				d.SetId("")
+				return nil
+			}
+		}
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,133 @@
+package scaleway
+
+import (
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func init() {
+	resource.AddTestSweepers("scaleway_image", &resource.Sweeper{
+		Name: "scaleway_image",
+		F:    testSweepImage,
+	})
+}
+
+func testSweepImage(region string) error {
+	client, err := sharedClientForRegion(region)
+	if err != nil {
+		return fmt.Errorf("error getting client: %s", err)
+	}
+
+	scaleway := client.(*Client).scaleway
+	log.Printf("[DEBUG] Destroying the images in (%s)", region)
+
+	images, err := scaleway.GetImages()
+	if err != nil {
+		return fmt.Errorf("Error describing images in Sweeper: %s", err)
+	}
+
+	for _, image := range *images {
+		// images of the organization are merged into the marketplace listing
+		// under the MyImages category, using their ID as current version
+		if len(image.Categories) != 1 || image.Categories[0] != "MyImages" {
+			continue
+		}
+		if err := scaleway.DeleteImage(image.CurrentPublicVersion); err != nil {
+			return fmt.Errorf("Error deleting image in Sweeper: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func TestAccScalewayImage_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScalewayImageDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewayImageResourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayImageExists("scaleway_image.test"),
+					resource.TestCheckResourceAttr(
+						"scaleway_image.test", "name", "test"),
+					resource.TestCheckResourceAttr(
+						"scaleway_image.test", "architecture", "arm"),
+					resource.TestCheckResourceAttr(
+						"scaleway_image.test", "public", "false"),
+					resource.TestCheckResourceAttrPair(
+						"scaleway_image.test", "snapshot", "scaleway_snapshot.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckScalewayImageDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client).scaleway
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "scaleway_image" {
+			continue
+		}
+
+		_, err := client.GetImage(rs.Primary.ID)
+
+		if err == nil {
+			return fmt.Errorf("Image still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckScalewayImageExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Image ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client).scaleway
+		image, err := client.GetImage(rs.Primary.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if image.Identifier != rs.Primary.ID {
+			return fmt.Errorf("Record not found")
+		}
+
+		return nil
+	}
+}
+
+var testAccCheckScalewayImageResourceConfig = `
+resource "scaleway_volume" "test" {
+  name = "test"
+  size_in_gb = 2
+  type = "l_ssd"
+}
+
+resource "scaleway_snapshot" "test" {
+  name = "test"
+  volume = "${scaleway_volume.test.id}"
+}
+
+resource "scaleway_image" "test" {
+  name = "test"
+  snapshot = "${scaleway_snapshot.test.id}"
+  architecture = "arm"
+}
+`
@@ -14,10 +14,11 @@ func resourceScalewayIP() *schema.Resource {
 		Update: resourceScalewayIPUpdate,
 		Delete: resourceScalewayIPDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importRegionalResource,
 		},
 
 		Schema: map[string]*schema.Schema{
+			"region": regionSchema(),
 			"server": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -34,11 +35,12 @@ func resourceScalewayIP() *schema.Resource {
 }
 
 func resourceScalewayIPCreate(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
-	mu.Lock()
 	resp, err := scaleway.NewIP()
-	mu.Unlock()
 	if err != nil {
 		return err
 	}
@@ -48,7 +50,11 @@ func resourceScalewayIPCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceScalewayIPRead(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	d.Set("region", scaleway.Region)
 	log.Printf("[DEBUG] Reading IP\n")
 
 	resp, err := scaleway.GetIP(d.Id())
@@ -71,10 +77,13 @@ func resourceScalewayIPRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceScalewayIPUpdate(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	scalewayMutexKV.Lock(d.Id())
+	defer scalewayMutexKV.Unlock(d.Id())
 
 	if d.HasChange("server") {
 		if d.Get("server").(string) != "" {
@@ -92,12 +101,15 @@ func resourceScalewayIPUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceScalewayIPDelete(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	scalewayMutexKV.Lock(d.Id())
+	defer scalewayMutexKV.Unlock(d.Id())
 
-	err := scaleway.DeleteIP(d.Id())
+	err = scaleway.DeleteIP(d.Id())
 	if err != nil {
 		return err
 	}
@@ -90,15 +90,36 @@ func TestAccScalewayIP_Basic(t *testing.T) {
 	})
 }
 
-func testAccCheckScalewayIPDestroy(s *terraform.State) error {
-	client := testAccProvider.Meta().(*Client).scaleway
+func TestAccScalewayIP_Region(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScalewayIPDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewayIPConfig_Region,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayIPExists("scaleway_ip.base"),
+					testAccCheckScalewayIPExists("scaleway_ip.ams"),
+					resource.TestCheckResourceAttr("scaleway_ip.base", "region", "par1"),
+					resource.TestCheckResourceAttr("scaleway_ip.ams", "region", "ams1"),
+				),
+			},
+		},
+	})
+}
 
+func testAccCheckScalewayIPDestroy(s *terraform.State) error {
 	for _, rs := range s.RootModule().Resources {
-		if rs.Type != "scaleway" {
+		if rs.Type != "scaleway_ip" {
 			continue
 		}
 
-		_, err := client.GetIP(rs.Primary.ID)
+		client, err := testAccProvider.Meta().(*Client).forRegion(rs.Primary.Attributes["region"])
+		if err != nil {
+			return err
+		}
+		_, err = client.GetIP(rs.Primary.ID)
 
 		if err == nil {
 			return fmt.Errorf("IP still exists")
@@ -126,7 +147,10 @@ func testAccCheckScalewayIPExists(n string) resource.TestCheckFunc {
 			return fmt.Errorf("No IP ID is set")
 		}
 
-		client := testAccProvider.Meta().(*Client).scaleway
+		client, err := testAccProvider.Meta().(*Client).forRegion(rs.Primary.Attributes["region"])
+		if err != nil {
+			return err
+		}
 		ip, err := client.GetIP(rs.Primary.ID)
 
 		if err != nil {
@@ -183,6 +207,15 @@ resource "scaleway_ip" "base" {
 }
 `
 
+var testAccCheckScalewayIPConfig_Region = `
+resource "scaleway_ip" "base" {
+}
+
+resource "scaleway_ip" "ams" {
+  region = "ams1"
+}
+`
+
 var testAccCheckScalewayIPAttachConfig = fmt.Sprintf(`
 resource "scaleway_server" "base" {
   name = "test"
@@ -15,10 +15,11 @@ func resourceScalewaySecurityGroup() *schema.Resource {
 		Update: resourceScalewaySecurityGroupUpdate,
 		Delete: resourceScalewaySecurityGroupDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importRegionalResource,
 		},
 
 		Schema: map[string]*schema.Schema{
+			"region": regionSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -34,10 +35,10 @@ func resourceScalewaySecurityGroup() *schema.Resource {
 }
 
 func resourceScalewaySecurityGroupCreate(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
-
-	mu.Lock()
-	defer mu.Unlock()
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
 	req := api.ScalewayNewSecurityGroup{
 		Name:         d.Get("name").(string),
@@ -45,7 +46,7 @@ func resourceScalewaySecurityGroupCreate(d *schema.ResourceData, m interface{})
 		Organization: scaleway.Organization,
 	}
 
-	err := scaleway.PostSecurityGroup(req)
+	err = scaleway.PostSecurityGroup(req)
 	if err != nil {
 		if serr, ok := err.(api.ScalewayAPIError); ok {
 			log.Printf("[DEBUG] Error creating security group: %q\n", serr.APIMessage)
@@ -74,7 +75,11 @@ func resourceScalewaySecurityGroupCreate(d *schema.ResourceData, m interface{})
 }
 
 func resourceScalewaySecurityGroupRead(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	d.Set("region", scaleway.Region)
 	resp, err := scaleway.GetASecurityGroup(d.Id())
 
 	if err != nil {
@@ -97,10 +102,13 @@ func resourceScalewaySecurityGroupRead(d *schema.ResourceData, m interface{}) er
 }
 
 func resourceScalewaySecurityGroupUpdate(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	scalewayMutexKV.Lock(d.Id())
+	defer scalewayMutexKV.Unlock(d.Id())
 
 	var req = api.ScalewayUpdateSecurityGroup{
 		Organization: scaleway.Organization,
@@ -118,12 +126,15 @@ func resourceScalewaySecurityGroupUpdate(d *schema.ResourceData, m interface{})
 }
 
 func resourceScalewaySecurityGroupDelete(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	scalewayMutexKV.Lock(d.Id())
+	defer scalewayMutexKV.Unlock(d.Id())
 
-	err := scaleway.DeleteSecurityGroup(d.Id())
+	err = scaleway.DeleteSecurityGroup(d.Id())
 	if err != nil {
 		if serr, ok := err.(api.ScalewayAPIError); ok {
 			log.Printf("[DEBUG] error reading Security Group Rule: %q\n", serr.APIMessage)
@@ -15,6 +15,7 @@ func resourceScalewaySecurityGroupRule() *schema.Resource {
 		Update: resourceScalewaySecurityGroupRuleUpdate,
 		Delete: resourceScalewaySecurityGroupRuleDelete,
 		Schema: map[string]*schema.Schema{
+			"region": regionSchema(),
 			"security_group": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -71,10 +72,14 @@ func resourceScalewaySecurityGroupRule() *schema.Resource {
 }
 
 func resourceScalewaySecurityGroupRuleCreate(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	securityGroupID := d.Get("security_group").(string)
+	scalewayMutexKV.Lock(securityGroupID)
+	defer scalewayMutexKV.Unlock(securityGroupID)
 
 	req := api.NewSecurityGroupRule{
 		Action:       d.Get("action").(string),
@@ -103,7 +108,11 @@ func resourceScalewaySecurityGroupRuleCreate(d *schema.ResourceData, m interface
 }
 
 func resourceScalewaySecurityGroupRuleRead(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	d.Set("region", scaleway.Region)
 	rule, err := scaleway.GetASecurityGroupRule(d.Get("security_group").(string), d.Id())
 
 	if err != nil {
@@ -129,10 +138,14 @@ func resourceScalewaySecurityGroupRuleRead(d *schema.ResourceData, m interface{}
 }
 
 func resourceScalewaySecurityGroupRuleUpdate(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	securityGroupID := d.Get("security_group").(string)
+	scalewayMutexKV.Lock(securityGroupID)
+	defer scalewayMutexKV.Unlock(securityGroupID)
 
 	var req = api.NewSecurityGroupRule{
 		Action:       d.Get("action").(string),
@@ -152,12 +165,16 @@ func resourceScalewaySecurityGroupRuleUpdate(d *schema.ResourceData, m interface
 }
 
 func resourceScalewaySecurityGroupRuleDelete(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	securityGroupID := d.Get("security_group").(string)
+	scalewayMutexKV.Lock(securityGroupID)
+	defer scalewayMutexKV.Unlock(securityGroupID)
 
-	err := scaleway.DeleteSecurityGroupRule(d.Get("security_group").(string), d.Id())
+	err = scaleway.DeleteSecurityGroupRule(d.Get("security_group").(string), d.Id())
 	if err != nil {
 		if serr, ok := err.(api.ScalewayAPIError); ok {
 			log.Printf("[DEBUG] error reading Security Group Rule: %q\n", serr.APIMessage)
@@ -3,8 +3,13 @@ package scaleway
 import (
 	"fmt"
 	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
 	"github.com/nicolai86/scaleway-sdk/api"
 )
 
@@ -17,33 +22,74 @@ func resourceScalewayServer() *schema.Resource {
 		Update: resourceScalewayServerUpdate,
 		Delete: resourceScalewayServerDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importRegionalResource,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Update: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
 		},
 
 		Schema: map[string]*schema.Schema{
+			"region": regionSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
 				Description: "The name of the server",
 			},
 			"image": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The base image of the server",
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"root_volume_snapshot"},
+				Description:   "The base image of the server",
+			},
+			"root_volume_snapshot": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"image"},
+				Description:   "The snapshot the root volume of the server is restored from",
 			},
 			"type": {
 				Type:         schema.TypeString,
 				Required:     true,
-				ForceNew:     true,
-				Description:  "The instance type of the server",
+				Description:  "The instance type of the server, changed in place by power cycling the server",
 				ValidateFunc: validateServerType,
 			},
 			"bootscript": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true,
 				Description: "The boot configuration of the server",
 			},
+			"reboot_on_change": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Reboot running servers when the bootscript changes, so the new kernel is used",
+			},
+			"wait_for": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "running",
+				ValidateFunc: validateServerWaitFor,
+				Description:  "What to wait for after powering the server on at creation (running, booted, ssh)",
+			},
+			"ssh_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "5m",
+				ValidateFunc: validateDuration,
+				Description:  "How long to wait for SSH to accept connections when wait_for is ssh",
+			},
+			"volumes_on_destroy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "delete",
+				ValidateFunc: validateVolumesOnDestroy,
+				Description:  "Whether additional volumes are deleted or detached when the server is destroyed",
+			},
 			"tags": {
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
@@ -60,7 +106,6 @@ func resourceScalewayServer() *schema.Resource {
 			"volume": {
 				Type:     schema.TypeList,
 				Optional: true,
-				ForceNew: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"size_in_gb": {
@@ -85,7 +130,45 @@ func resourceScalewayServer() *schema.Resource {
 						},
 					},
 				},
-				Description: "Volumes attached to the server on creation",
+				Description: "Additional volumes attached to the server, changed in place by power cycling the server",
+			},
+			"root_volume": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				MaxItems:    1,
+				Description: "The root volume of the server, defaults to the root volume of the image",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"size_in_gb": {
+							Type:     schema.TypeInt,
+							Optional: true,
+							Computed: true,
+							ForceNew: true,
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ForceNew:     true,
+							ValidateFunc: validateVolumeType,
+						},
+						"delete_on_termination": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"volume_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"user_data": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Description: "The user data associated with the server, e.g. cloud-init",
 			},
 			"enable_ipv6": {
 				Type:        schema.TypeBool,
@@ -114,11 +197,89 @@ func resourceScalewayServer() *schema.Resource {
 				Computed:    true,
 				Description: "the public IPv6 address of the server, if enabled",
 			},
-			"state": {
+			"public_ipv6_gateway": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the gateway of the public IPv6 address of the server, if enabled",
+			},
+			"public_ipv6_netmask": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the netmask of the public IPv6 address of the server, if enabled",
+			},
+			"public_dns": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the DNS name of the public IP address of the server",
+			},
+			"private_dns": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the DNS name of the private IP address of the server",
+			},
+			"hostname": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the hostname of the server",
+			},
+			"arch": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the architecture of the server",
+			},
+			"location": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "the location of the server in the Scaleway infrastructure",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"zone_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"platform_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cluster_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"hypervisor_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"chassis_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"blade_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"node_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"creation_date": {
 				Type:        schema.TypeString,
-				Optional:    true,
 				Computed:    true,
-				Description: "the server state (running, stopped)",
+				Description: "the date the server was created",
+			},
+			"modification_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the date the server was last modified",
+			},
+			"state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				Description:  "the server state (running, stopped, standby)",
+				ValidateFunc: validateServerState,
 			},
 			"state_detail": {
 				Type:        schema.TypeString,
@@ -143,19 +304,351 @@ func attachIP(scaleway *api.ScalewayAPI, serverID, IPAddress string) error {
 	return fmt.Errorf("Failed to find IP with ip %q to attach", IPAddress)
 }
 
-func resourceScalewayServerCreate(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+// flattenServerLocation returns the location of the server, which is only known
+// while the server is allocated to a node.
+func flattenServerLocation(server *api.ScalewayServer) []map[string]interface{} {
+	if server.Location.ZoneID == "" && server.Location.Node == "" {
+		return nil
+	}
+	return []map[string]interface{}{{
+		"zone_id":       server.Location.ZoneID,
+		"platform_id":   server.Location.Platform,
+		"cluster_id":    server.Location.Cluster,
+		"hypervisor_id": server.Location.Hypervisor,
+		"chassis_id":    server.Location.Chassis,
+		"blade_id":      server.Location.Blade,
+		"node_id":       server.Location.Node,
+	}}
+}
+
+// readServerUserData fetches all user data key/value pairs of a server.
+// readServerUserData reads the given user data keys of a server, omitting
+// those which no longer exist.
+func readServerUserData(scaleway *api.ScalewayAPI, serverID string, keys map[string]interface{}) (map[string]string, error) {
+	remoteKeys, err := scaleway.GetUserdatas(serverID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	userData := make(map[string]string)
+	for _, key := range remoteKeys.UserData {
+		if _, ok := keys[key]; !ok {
+			continue
+		}
+		value, err := scaleway.GetUserdata(serverID, key, false)
+		if err != nil {
+			return nil, err
+		}
+		userData[key] = value.String()
+	}
+	return userData, nil
+}
+
+// diffServerType checks changes of the type of a server. Types of another
+// architecture require an image of that architecture, so they can only be
+// changed by replacing the server along with its image. Changing the type
+// without the image fails during plan, rather than once the old server has been
+// replaced, even if another change replaces the server.
+func diffServerType(diff *terraform.InstanceDiff) (*terraform.InstanceDiff, error) {
+	attr, ok := diff.Attributes["type"]
+	if !ok || attr == nil || attr.Old == "" || attr.NewComputed {
+		return diff, nil
+	}
+	from, to := commercialTypeArch(attr.Old), commercialTypeArch(attr.New)
+	if from == to {
+		return diff, nil
+	}
+
+	if image, ok := diff.Attributes["image"]; !ok || image == nil || image.Old == image.New && !image.NewComputed {
+		return nil, fmt.Errorf("Cannot change type from %q to %q: the server runs an %s image, "+
+			"change the image to one of the %s architecture to replace the server", attr.Old, attr.New, from, to)
+	}
+	attr.RequiresNew = true
+	return diff, nil
+}
+
+// changeServerType changes the commercial type of a server, which requires the
+// server to be powered off. Types of another architecture require a new image,
+// which replaces the server.
+func changeServerType(scaleway *api.ScalewayAPI, serverID, commercialType string, timeout time.Duration) error {
+	server, err := scaleway.GetServer(serverID)
+	if err != nil {
+		return err
+	}
+	if arch := commercialTypeArch(commercialType); server.Arch != "" && server.Arch != arch {
+		return fmt.Errorf("Cannot change the type of server %q to %q: the server runs an %s image, "+
+			"change the image to one of the %s architecture to replace the server instead", serverID, commercialType, server.Arch, arch)
+	}
+
+	log.Printf("[DEBUG] Changing type of server %q from %q to %q\n", serverID, server.CommercialType, commercialType)
+	return withServerStopped(scaleway, serverID, timeout, func(time.Duration) error {
+		return patchServerType(scaleway, serverID, commercialType)
+	})
+}
+
+// serverVolumeKey identifies the volume blocks of a server by size and type,
+// which is all the configuration knows about their volumes.
+func serverVolumeKey(sizeInGB, volumeType string) string {
+	return sizeInGB + "/" + volumeType
+}
+
+// matchServerVolumes matches the volume blocks of the new configuration of a
+// server with the blocks of its state, given as serverVolumeKey. Old blocks
+// without volume, e.g. of size 0, are given as empty keys: they match no block
+// and can be replaced by any block. Blocks are matched in order, so removing or
+// adding a block keeps the volumes of the other blocks, and moving a block keeps
+// its volume. It returns the index of the old block matched by each new block,
+// or -1, and the new blocks which replace an old volume at the same position,
+// i.e. change its size or type.
+func matchServerVolumes(oldKeys, newKeys []string) (matches []int, changed []int) {
+	// longest common subsequence of the old and new blocks
+	lengths := make([][]int, len(oldKeys)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(newKeys)+1)
+	}
+	for i := len(oldKeys) - 1; i >= 0; i-- {
+		for j := len(newKeys) - 1; j >= 0; j-- {
+			if oldKeys[i] != "" && oldKeys[i] == newKeys[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	matches = make([]int, len(newKeys))
+	for j := range matches {
+		matches[j] = -1
+	}
+	matched := make(map[int]bool)
+	var anchors [][2]int
+	for i, j := 0, 0; i < len(oldKeys) && j < len(newKeys); {
+		switch {
+		case oldKeys[i] != "" && oldKeys[i] == newKeys[j]:
+			matches[j] = i
+			matched[i] = true
+			anchors = append(anchors, [2]int{i, j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	// blocks moved to another position keep their volume
+	for j := range matches {
+		for i := 0; matches[j] == -1 && i < len(oldKeys); i++ {
+			if !matched[i] && oldKeys[i] != "" && oldKeys[i] == newKeys[j] {
+				matches[j] = i
+				matched[i] = true
+			}
+		}
+	}
+
+	// blocks left between the same matched blocks of both lists are changed
+	anchors = append(anchors, [2]int{len(oldKeys), len(newKeys)})
+	previousOld, previousNew := 0, 0
+	for _, anchor := range anchors {
+		removed := false
+		for i := previousOld; i < anchor[0]; i++ {
+			removed = removed || !matched[i] && oldKeys[i] != ""
+		}
+		for j := previousNew; j < anchor[1]; j++ {
+			if removed && matches[j] == -1 {
+				changed = append(changed, j)
+			}
+		}
+		previousOld, previousNew = anchor[0]+1, anchor[1]+1
+	}
+	return matches, changed
+}
+
+// diffServerVolumes checks changes of the volumes of a server. Volumes can not
+// be resized, so changing the size or type of a volume fails during plan rather
+// than replacing it by an empty volume. Attaching and detaching volumes reboots
+// the server, which the plan shows as a new state_detail.
+func diffServerVolumes(s *terraform.InstanceState, diff *terraform.InstanceDiff) (*terraform.InstanceDiff, error) {
+	if s == nil || s.ID == "" || diff.RequiresNew() {
+		return diff, nil
+	}
+	changed := false
+	for k := range diff.Attributes {
+		changed = changed || strings.HasPrefix(k, "volume.")
+	}
+	if !changed {
+		return diff, nil
+	}
+
+	attribute := func(k string) string {
+		if attr, ok := diff.Attributes[k]; ok && !attr.NewRemoved {
+			return attr.New
+		}
+		return s.Attributes[k]
+	}
+	if attr, ok := diff.Attributes["volume.#"]; !ok || !attr.NewComputed {
+		oldCount, _ := strconv.Atoi(s.Attributes["volume.#"])
+		newCount, _ := strconv.Atoi(attribute("volume.#"))
+		oldKeys, newKeys := make([]string, oldCount), make([]string, newCount)
+		for i := range oldKeys {
+			prefix := fmt.Sprintf("volume.%d.", i)
+			if s.Attributes[prefix+"volume_id"] != "" {
+				oldKeys[i] = serverVolumeKey(s.Attributes[prefix+"size_in_gb"], s.Attributes[prefix+"type"])
+			}
+		}
+		for i := range newKeys {
+			prefix := fmt.Sprintf("volume.%d.", i)
+			newKeys[i] = serverVolumeKey(attribute(prefix+"size_in_gb"), attribute(prefix+"type"))
+		}
+
+		if _, resized := matchServerVolumes(oldKeys, newKeys); len(resized) > 0 {
+			prefix := fmt.Sprintf("volume.%d.", resized[0])
+			return nil, fmt.Errorf("Cannot change volume %d to %s GB of type %s: volumes can not be resized, "+
+				"remove the volume and add a new one in separate applies", resized[0], attribute(prefix+"size_in_gb"), attribute(prefix+"type"))
+		}
+	}
+
+	diff.Attributes["state_detail"] = &terraform.ResourceAttrDiff{
+		Old:         s.Attributes["state_detail"],
+		NewComputed: true,
+	}
+	return diff, nil
+}
+
+// updateServerVolumes attaches and detaches the additional volumes of the server
+// in place. Volume blocks are matched with the volumes of the state by
+// matchServerVolumes, volumes of removed blocks are deleted. Volumes attached
+// through scaleway_volume_attachment are kept.
+func updateServerVolumes(scaleway *api.ScalewayAPI, d *schema.ResourceData, timeout time.Duration) error {
+	o, n := d.GetChange("volume")
+	oldVolumes, newVolumes := o.([]interface{}), n.([]interface{})
+
+	key := func(v interface{}) string {
+		volume := v.(map[string]interface{})
+		return serverVolumeKey(strconv.Itoa(volume["size_in_gb"].(int)), volume["type"].(string))
+	}
+	var oldKeys, newKeys []string
+	for _, v := range oldVolumes {
+		if v.(map[string]interface{})["volume_id"].(string) == "" {
+			oldKeys = append(oldKeys, "")
+			continue
+		}
+		oldKeys = append(oldKeys, key(v))
+	}
+	for _, v := range newVolumes {
+		newKeys = append(newKeys, key(v))
+	}
+	matches, changed := matchServerVolumes(oldKeys, newKeys)
+	if len(changed) > 0 {
+		// prevented during plan by diffServerVolumes
+		return fmt.Errorf("Volumes of server %q can not be resized", d.Id())
+	}
+
+	server, err := scaleway.GetServer(d.Id())
+	if err != nil {
+		return err
+	}
+	current := sortedServerVolumes(server)
+	if len(current) == 0 {
+		return fmt.Errorf("Server %q has no root volume, its volumes can not be changed", d.Id())
+	}
+
+	var created []string
+	attached := []api.ScalewayVolume{}
+	for j, v := range newVolumes {
+		volume := v.(map[string]interface{})
+		sizeInGB := volume["size_in_gb"].(int)
+		volume["volume_id"] = ""
+
+		if i := matches[j]; i != -1 {
+			volume["volume_id"] = oldVolumes[i].(map[string]interface{})["volume_id"].(string)
+		} else if sizeInGB > 0 {
+			id, err := scaleway.PostVolume(api.ScalewayVolumeDefinition{
+				Size: uint64(sizeInGB) * gb,
+				Type: volume["type"].(string),
+				Name: fmt.Sprintf("%s-%d", d.Get("name").(string), sizeInGB),
+			})
+			if err != nil {
+				return err
+			}
+			log.Printf("[DEBUG] Created volume %q for server %q\n", id, d.Id())
+			volume["volume_id"] = id
+			created = append(created, id)
+		}
+
+		if id := volume["volume_id"].(string); id != "" {
+			attached = append(attached, api.ScalewayVolume{Identifier: id})
+		}
+		newVolumes[j] = volume
+	}
+
+	managed := make(map[string]bool)
+	for _, v := range oldVolumes {
+		if id := v.(map[string]interface{})["volume_id"].(string); id != "" {
+			managed[id] = true
+		}
+	}
+	volumes := append([]api.ScalewayVolume{current[0]}, attached...)
+	for _, volume := range current[1:] {
+		if !managed[volume.Identifier] {
+			volumes = append(volumes, volume)
+		}
+	}
+
+	if err := patchServerVolumes(scaleway, d.Id(), volumes, timeout); err != nil {
+		for _, id := range created {
+			if err := scaleway.DeleteVolume(id); err != nil {
+				log.Printf("[DEBUG] Error deleting unattached volume %q: %s\n", id, err)
+			}
+		}
+		return err
+	}
+	d.Set("volume", newVolumes)
+
+	kept := make(map[int]bool)
+	for _, i := range matches {
+		kept[i] = true
+	}
+	for i, v := range oldVolumes {
+		id := v.(map[string]interface{})["volume_id"].(string)
+		if kept[i] || id == "" {
+			continue
+		}
+		log.Printf("[DEBUG] Deleting volume %q removed from server %q\n", id, d.Id())
+		if err := scaleway.DeleteVolume(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func resourceScalewayServerCreate(d *schema.ResourceData, m interface{}) error {
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	// all waits of the creation share its timeout
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutCreate))
 
 	image := d.Get("image").(string)
-	var server = api.ScalewayServerDefinition{
-		Name:          d.Get("name").(string),
-		Image:         String(image),
-		Organization:  scaleway.Organization,
-		EnableIPV6:    d.Get("enable_ipv6").(bool),
-		SecurityGroup: d.Get("security_group").(string),
+	snapshotID := d.Get("root_volume_snapshot").(string)
+	if image == "" && snapshotID == "" {
+		return fmt.Errorf("One of image or root_volume_snapshot must be set")
+	}
+
+	var server = scalewayServerDefinition{
+		ScalewayServerDefinition: api.ScalewayServerDefinition{
+			Name:          d.Get("name").(string),
+			Organization:  scaleway.Organization,
+			EnableIPV6:    d.Get("enable_ipv6").(bool),
+			SecurityGroup: d.Get("security_group").(string),
+		},
+	}
+	if image != "" {
+		server.Image = String(image)
 	}
 
 	server.DynamicIPRequired = Bool(d.Get("dynamic_ip_required").(bool))
@@ -163,10 +656,53 @@ func resourceScalewayServerCreate(d *schema.ResourceData, m interface{}) error {
 
 	if bootscript, ok := d.GetOk("bootscript"); ok {
 		server.Bootscript = String(bootscript.(string))
+	} else if snapshotID != "" {
+		return fmt.Errorf("bootscript must be set to boot servers from root_volume_snapshot")
+	}
+
+	server.Volumes = make(map[string]interface{})
+	sizeInGB, volumeType := 0, ""
+	if raw, ok := d.GetOk("root_volume"); ok {
+		rootVolume := raw.([]interface{})[0].(map[string]interface{})
+		sizeInGB, volumeType = rootVolume["size_in_gb"].(int), rootVolume["type"].(string)
+	}
+
+	rootVolumeID := ""
+	if snapshotID != "" {
+		// servers without image boot from an existing root volume
+		snapshot, err := scaleway.GetSnapshot(snapshotID)
+		if err != nil {
+			return err
+		}
+		if sizeInGB > 0 && uint64(sizeInGB)*gb != snapshot.Size {
+			return fmt.Errorf("root_volume size_in_gb must match the size of root_volume_snapshot, %d GB", snapshot.Size/gb)
+		}
+		if volumeType == "" {
+			volumeType = snapshot.VolumeType
+		}
+
+		log.Printf("[DEBUG] Restoring root volume of server %q from snapshot %q\n", server.Name, snapshotID)
+		rootVolumeID, err = postVolume(scaleway, scalewayVolumeDefinition{
+			ScalewayVolumeDefinition: api.ScalewayVolumeDefinition{
+				Name: fmt.Sprintf("%s-root", server.Name),
+				Size: snapshot.Size,
+				Type: volumeType,
+			},
+			BaseSnapshot: snapshotID,
+		})
+		if err != nil {
+			return err
+		}
+		server.Volumes["0"] = rootVolumeID
+	} else if sizeInGB > 0 || volumeType != "" {
+		server.Volumes["0"] = scalewayServerVolumeDefinition{
+			Name:       fmt.Sprintf("%s-root", server.Name),
+			Size:       uint64(sizeInGB) * gb,
+			VolumeType: volumeType,
+		}
 	}
 
 	if vs, ok := d.GetOk("volume"); ok {
-		server.Volumes = make(map[string]string)
 
 		volumes := vs.([]interface{})
 		for i, v := range volumes {
@@ -196,36 +732,62 @@ func resourceScalewayServerCreate(d *schema.ResourceData, m interface{}) error {
 		}
 	}
 
-	id, err := scaleway.PostServer(server)
+	id, err := postServer(scaleway, server)
 	if err != nil {
+		if rootVolumeID != "" {
+			if err := scaleway.DeleteVolume(rootVolumeID); err != nil {
+				log.Printf("[DEBUG] Error deleting root volume %q: %s\n", rootVolumeID, err)
+			}
+		}
 		return err
 	}
 
 	d.SetId(id)
+
+	if raw, ok := d.GetOk("user_data"); ok {
+		for key, value := range raw.(map[string]interface{}) {
+			if err := scaleway.PatchUserdata(id, key, []byte(value.(string)), false); err != nil {
+				return err
+			}
+		}
+	}
+
 	if d.Get("state").(string) != "stopped" {
 		err = scaleway.PostServerAction(id, "poweron")
 		if err != nil {
 			return err
 		}
 
-		err = waitForServerState(scaleway, id, "running")
+		if err := waitForServerState(scaleway, id, "running", time.Until(deadline)); err != nil {
+			return err
+		}
 
 		if v, ok := d.GetOk("public_ip"); ok {
 			if err := attachIP(scaleway, d.Id(), v.(string)); err != nil {
 				return err
 			}
 		}
+
+		if err := waitForServerBoot(d, scaleway, id, time.Until(deadline)); err != nil {
+			return err
+		}
 	}
 
-	if err != nil {
-		return err
+	if d.Get("state").(string) == "standby" {
+		if err := setServerState(scaleway, id, "standby", time.Until(deadline)); err != nil {
+			return err
+		}
 	}
 
 	return resourceScalewayServerRead(d, m)
 }
 
 func resourceScalewayServerRead(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	d.Set("region", scaleway.Region)
 	server, err := scaleway.GetServer(d.Id())
 
 	if err != nil {
@@ -244,18 +806,63 @@ func resourceScalewayServerRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("name", server.Name)
 	d.Set("image", server.Image.Identifier)
 	d.Set("type", server.CommercialType)
+	if server.Bootscript != nil {
+		d.Set("bootscript", server.Bootscript.Identifier)
+	}
+	if rootVolume, ok := server.Volumes["0"]; ok {
+		d.Set("root_volume", []map[string]interface{}{{
+			"size_in_gb":            int(rootVolume.Size / gb),
+			"type":                  rootVolume.VolumeType,
+			"delete_on_termination": deleteRootVolumeOnTermination(d),
+			"volume_id":             rootVolume.Identifier,
+		}})
+	}
+	// reboot_on_change, volumes_on_destroy, wait_for and ssh_timeout are not
+	// stored by Scaleway, imported servers use the defaults
+	d.Set("reboot_on_change", d.Get("reboot_on_change").(bool))
+	d.Set("volumes_on_destroy", volumesOnDestroy(d))
+	if _, ok := d.GetOk("wait_for"); !ok {
+		d.Set("wait_for", "running")
+	}
+	if _, ok := d.GetOk("ssh_timeout"); !ok {
+		d.Set("ssh_timeout", "5m")
+	}
 	d.Set("enable_ipv6", server.EnableIPV6)
 	d.Set("private_ip", server.PrivateIP)
 	d.Set("public_ip", server.PublicAddress.IP)
 
-	if server.EnableIPV6 {
+	if server.EnableIPV6 && server.IPV6 != nil {
 		d.Set("public_ipv6", server.IPV6.Address)
+		d.Set("public_ipv6_gateway", server.IPV6.Gateway)
+		d.Set("public_ipv6_netmask", server.IPV6.Netmask)
+	} else {
+		d.Set("public_ipv6", "")
+		d.Set("public_ipv6_gateway", "")
+		d.Set("public_ipv6_netmask", "")
 	}
 
-	d.Set("state", server.State)
+	d.Set("public_dns", server.DNSPublic)
+	d.Set("private_dns", server.DNSPrivate)
+	d.Set("hostname", server.Hostname)
+	d.Set("arch", server.Arch)
+	d.Set("creation_date", server.CreationDate)
+	d.Set("modification_date", server.ModificationDate)
+	d.Set("location", flattenServerLocation(server))
+
+	d.Set("state", serverState(server.State))
 	d.Set("state_detail", server.StateDetail)
 	d.Set("tags", server.Tags)
 
+	// only the user data keys managed through the server are refreshed, keys
+	// of scaleway_user_data resources are left to them
+	if raw, ok := d.GetOk("user_data"); ok {
+		userData, err := readServerUserData(scaleway, server.Identifier, raw.(map[string]interface{}))
+		if err != nil {
+			return err
+		}
+		d.Set("user_data", userData)
+	}
+
 	d.SetConnInfo(map[string]string{
 		"type": "ssh",
 		"host": server.PublicAddress.IP,
@@ -265,10 +872,16 @@ func resourceScalewayServerRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceScalewayServerUpdate(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+
+	scalewayMutexKV.Lock(d.Id())
+	defer scalewayMutexKV.Unlock(d.Id())
 
-	mu.Lock()
-	defer mu.Unlock()
+	// all waits of the update share its timeout
+	deadline := time.Now().Add(d.Timeout(schema.TimeoutUpdate))
 
 	var req api.ScalewayServerPatchDefinition
 	if d.HasChange("name") {
@@ -294,6 +907,10 @@ func resourceScalewayServerUpdate(d *schema.ResourceData, m interface{}) error {
 		req.DynamicIPRequired = Bool(d.Get("dynamic_ip_required").(bool))
 	}
 
+	if d.HasChange("bootscript") {
+		req.Bootscript = String(d.Get("bootscript").(string))
+	}
+
 	if d.HasChange("security_group") {
 		req.SecurityGroup = &api.ScalewaySecurityGroup{
 			Identifier: d.Get("security_group").(string),
@@ -304,6 +921,61 @@ func resourceScalewayServerUpdate(d *schema.ResourceData, m interface{}) error {
 		return fmt.Errorf("Failed patching scaleway server: %q", err)
 	}
 
+	// servers are powered off before and powered on after other changes requiring a power cycle
+	state := d.Get("state").(string)
+	if d.HasChange("state") && state != "running" {
+		if err := setServerState(scaleway, d.Id(), state, time.Until(deadline)); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("type") {
+		if err := changeServerType(scaleway, d.Id(), d.Get("type").(string), time.Until(deadline)); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("volume") {
+		if err := updateServerVolumes(scaleway, d, time.Until(deadline)); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("state") && state == "running" {
+		if err := setServerState(scaleway, d.Id(), state, time.Until(deadline)); err != nil {
+			return err
+		}
+	}
+
+	// servers which have been booted by the changes above already use the new bootscript
+	if d.HasChange("bootscript") && d.Get("reboot_on_change").(bool) && !d.HasChange("type") && !d.HasChange("state") && !d.HasChange("volume") {
+		if err := rebootServer(scaleway, d.Id(), time.Until(deadline)); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("user_data") {
+		o, n := d.GetChange("user_data")
+		oldData, newData := o.(map[string]interface{}), n.(map[string]interface{})
+
+		for key := range oldData {
+			if _, ok := newData[key]; ok {
+				continue
+			}
+			if err := scaleway.DeleteUserdata(d.Id(), key, false); err != nil {
+				return err
+			}
+		}
+		for key, value := range newData {
+			if oldValue, ok := oldData[key]; ok && oldValue == value {
+				continue
+			}
+			if err := scaleway.PatchUserdata(d.Id(), key, []byte(value.(string)), false); err != nil {
+				return err
+			}
+		}
+	}
+
 	if d.HasChange("public_ip") {
 		ips, err := scaleway.GetIPS()
 		if err != nil {
@@ -336,21 +1008,21 @@ func resourceScalewayServerUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceScalewayServerDelete(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	scalewayMutexKV.Lock(d.Id())
+	defer scalewayMutexKV.Unlock(d.Id())
 
 	s, err := scaleway.GetServer(d.Id())
 	if err != nil {
 		return err
 	}
 
-	if s.State == "stopped" {
-		return deleteStoppedServer(scaleway, s)
-	}
-
-	err = deleteRunningServer(scaleway, s)
+	deleteVolumes := volumesOnDestroy(d) == "delete"
+	err = deleteServer(scaleway, s, deleteRootVolumeOnTermination(d), deleteVolumes, d.Timeout(schema.TimeoutDelete))
 
 	if err == nil {
 		d.SetId("")
@@ -358,3 +1030,58 @@ func resourceScalewayServerDelete(d *schema.ResourceData, m interface{}) error {
 
 	return err
 }
+
+// deleteRootVolumeOnTermination returns whether the root volume is deleted along
+// with the server, which is the default.
+func deleteRootVolumeOnTermination(d *schema.ResourceData) bool {
+	if raw, ok := d.GetOk("root_volume"); ok {
+		if rootVolume, ok := raw.([]interface{})[0].(map[string]interface{}); ok {
+			return rootVolume["delete_on_termination"].(bool)
+		}
+	}
+	return true
+}
+
+// waitForServerBoot waits for the running server to reach the stage of the boot
+// requested by wait_for, within timeout except for SSH, bound by ssh_timeout.
+func waitForServerBoot(d *schema.ResourceData, scaleway *api.ScalewayAPI, serverID string, timeout time.Duration) error {
+	waitFor := d.Get("wait_for").(string)
+	if waitFor == "running" {
+		return nil
+	}
+
+	if err := waitForServerBooted(scaleway, serverID, timeout); err != nil {
+		return err
+	}
+	if waitFor != "ssh" {
+		return nil
+	}
+
+	server, err := scaleway.GetServer(serverID)
+	if err != nil {
+		return err
+	}
+	address := server.PublicAddress.IP
+	if address == "" {
+		address = server.PrivateIP
+	}
+	if address == "" {
+		return fmt.Errorf("Server %q has no address to connect to with SSH", serverID)
+	}
+
+	sshTimeout, err := time.ParseDuration(d.Get("ssh_timeout").(string))
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Waiting for SSH on server %q at %s\n", serverID, address)
+	return waitForTCP(net.JoinHostPort(address, "22"), sshTimeout)
+}
+
+// volumesOnDestroy returns what happens to the additional volumes of the server
+// when it is destroyed, delete or detach.
+func volumesOnDestroy(d *schema.ResourceData) string {
+	if v, ok := d.GetOk("volumes_on_destroy"); ok {
+		return v.(string)
+	}
+	return "delete"
+}
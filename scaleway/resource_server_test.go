@@ -3,8 +3,14 @@ package scaleway
 import (
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -31,14 +37,7 @@ func testSweepServer(region string) error {
 	}
 
 	for _, server := range *servers {
-		var err error
-		if server.State == "stopped" {
-			err = deleteStoppedServer(scaleway, &server)
-		} else {
-			err = deleteRunningServer(scaleway, &server)
-		}
-
-		if err != nil {
+		if err := deleteServer(scaleway, &server, true, true, defaultTimeout); err != nil {
 			return fmt.Errorf("Error deleting server in Sweeper: %s", err)
 		}
 	}
@@ -63,6 +62,15 @@ func TestAccScalewayServer_Basic(t *testing.T) {
 						"scaleway_server.base", "name", "test"),
 					resource.TestCheckResourceAttr(
 						"scaleway_server.base", "tags.0", "terraform-test"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "arch", "arm"),
+					resource.TestCheckResourceAttrSet(
+						"scaleway_server.base", "hostname"),
+					resource.TestCheckResourceAttrSet(
+						"scaleway_server.base", "creation_date"),
+					resource.TestCheckResourceAttrSet(
+						"scaleway_server.base", "location.0.zone_id"),
+					testAccCheckScalewayServerDNS("scaleway_server.base"),
 				),
 			},
 			resource.TestStep{
@@ -99,6 +107,8 @@ func TestAccScalewayServer_ExistingIP(t *testing.T) {
 }
 
 func TestAccScalewayServer_Volumes(t *testing.T) {
+	var serverID, volumeID string
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
 		Providers:    testAccProviders,
@@ -129,12 +139,233 @@ func TestAccScalewayServer_Volumes(t *testing.T) {
 						"scaleway_server.base", "volume.2.type", "l_ssd"),
 					resource.TestCheckResourceAttr(
 						"scaleway_server.base", "volume.2.size_in_gb", "30"),
+					testAccCheckScalewayServerID("scaleway_server.base", &serverID),
+					testAccCheckScalewayServerVolumes("scaleway_server.base", 3),
+					func(s *terraform.State) error {
+						volumeID = s.RootModule().Resources["scaleway_server.base"].Primary.Attributes["volume.2.volume_id"]
+						return nil
+					},
 				),
 			},
+			resource.TestStep{
+				Config: testAccCheckScalewayServerVolumeConfig_Update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerID("scaleway_server.base", &serverID),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "volume.#", "2"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "volume.0.size_in_gb", "30"),
+					resource.TestCheckResourceAttrPtr(
+						"scaleway_server.base", "volume.0.volume_id", &volumeID),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "volume.1.size_in_gb", "40"),
+					resource.TestCheckResourceAttrSet(
+						"scaleway_server.base", "volume.1.volume_id"),
+					testAccCheckScalewayServerVolumes("scaleway_server.base", 3),
+					testAccCheckScalewayServerState("scaleway_server.base", "running"),
+				),
+			},
+			resource.TestStep{
+				Config:      testAccCheckScalewayServerVolumeConfig_Resize,
+				ExpectError: regexp.MustCompile("volumes can not be resized"),
+			},
 		},
 	})
 }
 
+func TestAccScalewayServer_RootVolume(t *testing.T) {
+	var rootVolumeID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckScalewayServerDestroy,
+			testAccCheckScalewayVolumeKept(&rootVolumeID),
+		),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewayServerConfig_RootVolume,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerExists("scaleway_server.base"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "root_volume.0.size_in_gb", "60"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "root_volume.0.type", "l_ssd"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "root_volume.0.delete_on_termination", "false"),
+					testAccCheckScalewayServerRootVolumeID("scaleway_server.base", &rootVolumeID),
+				),
+			},
+		},
+	})
+}
+
+func TestAccScalewayServer_RootVolumeSnapshot(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScalewayServerDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewayServerConfig_RootVolumeSnapshot,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerExists("scaleway_server.base"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "image", ""),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "root_volume.0.size_in_gb", "20"),
+					resource.TestCheckResourceAttrPair(
+						"scaleway_server.base", "bootscript",
+						"data.scaleway_bootscript.base", "id"),
+					resource.TestCheckResourceAttrPair(
+						"scaleway_server.base", "root_volume_snapshot",
+						"scaleway_snapshot.base", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccScalewayServer_WaitForBooted(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScalewayServerDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewayServerConfig_WaitForBooted,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerExists("scaleway_server.base"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "wait_for", "booted"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "state_detail", "booted"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccScalewayServer_DetachVolumesOnDestroy(t *testing.T) {
+	var volumeID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testAccCheckScalewayServerDestroy,
+			testAccCheckScalewayVolumeKept(&volumeID),
+		),
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewayServerConfig_DetachVolumesOnDestroy,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerExists("scaleway_server.base"),
+					testAccCheckScalewayServerVolumes("scaleway_server.base", 2),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "volumes_on_destroy", "detach"),
+					testAccCheckScalewayServerVolumeID("scaleway_server.base", 1, &volumeID),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckScalewayServerVolumeID stores the id of the volume of the server at the given index.
+func testAccCheckScalewayServerVolumeID(n string, index int, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Unknown resource: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*Client).scaleway
+		server, err := client.GetServer(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		volume, ok := server.Volumes[fmt.Sprint(index)]
+		if !ok {
+			return fmt.Errorf("Expected server %q to have a volume at index %d", server.Identifier, index)
+		}
+		*id = volume.Identifier
+		return nil
+	}
+}
+
+func testAccCheckScalewayServerRootVolumeID(n string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Unknown resource: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*Client).scaleway
+		server, err := client.GetServer(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		*id = rs.Primary.Attributes["root_volume.0.volume_id"]
+		if server.Volumes["0"].Identifier != *id {
+			return fmt.Errorf("Expected root volume %q but got %q", server.Volumes["0"].Identifier, *id)
+		}
+		return nil
+	}
+}
+
+// testAccCheckScalewayVolumeKept checks the volume survived the deletion of
+// the server, and deletes it.
+func testAccCheckScalewayVolumeKept(id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Client).scaleway
+		volume, err := client.GetVolume(*id)
+		if err != nil {
+			return fmt.Errorf("Expected volume %q to be kept: %s", *id, err)
+		}
+		if volume.Server != nil {
+			return fmt.Errorf("Expected volume %q to be detached", *id)
+		}
+		return client.DeleteVolume(*id)
+	}
+}
+
+// testAccCheckScalewayServerVolumes checks the number of volumes attached to
+// the server, including its root volume, and that they match the volume attribute.
+func testAccCheckScalewayServerVolumes(n string, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Unknown resource: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*Client).scaleway
+		server, err := client.GetServer(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(server.Volumes) != count {
+			return fmt.Errorf("Expected %d volumes to be attached but got %d", count, len(server.Volumes))
+		}
+		for key, value := range rs.Primary.Attributes {
+			if !strings.HasSuffix(key, ".volume_id") || value == "" {
+				continue
+			}
+			attached := false
+			for _, volume := range server.Volumes {
+				attached = attached || volume.Identifier == value
+			}
+			if !attached {
+				return fmt.Errorf("Volume %q of %s is not attached", value, key)
+			}
+		}
+		return nil
+	}
+}
+
 func TestAccScalewayServer_SecurityGroup(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -159,6 +390,236 @@ func TestAccScalewayServer_SecurityGroup(t *testing.T) {
 	})
 }
 
+func TestAccScalewayServer_UserData(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScalewayServerDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewayServerConfig_UserData,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerExists("scaleway_server.base"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "user_data.%", "2"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "user_data.cloud-init", "#cloud-config\n"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "user_data.role", "web"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckScalewayServerConfig_UserData_Update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerExists("scaleway_server.base"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "user_data.%", "1"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "user_data.role", "db"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckScalewayServerConfig_UserData_Resource,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerExists("scaleway_server.base"),
+					testAccCheckScalewayUserDataExists("scaleway_user_data.base"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "user_data.%", "1"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "user_data.role", "db"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccScalewayServer_ChangeType(t *testing.T) {
+	var serverID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScalewayServerDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccCheckScalewayServerConfig_Type, "VC1S"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerExists("scaleway_server.base"),
+					testAccCheckScalewayServerID("scaleway_server.base", &serverID),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "type", "VC1S"),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccCheckScalewayServerConfig_Type, "VC1M"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerID("scaleway_server.base", &serverID),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "type", "VC1M"),
+					resource.TestCheckResourceAttr(
+						"scaleway_server.base", "state", "running"),
+				),
+			},
+			resource.TestStep{
+				Config:      fmt.Sprintf(testAccCheckScalewayServerConfig_Type, "C1"),
+				ExpectError: regexp.MustCompile("Cannot change type"),
+			},
+		},
+	})
+}
+
+func TestAccScalewayServer_State(t *testing.T) {
+	var serverID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScalewayServerDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccCheckScalewayServerConfig_State, "stopped"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerID("scaleway_server.base", &serverID),
+					testAccCheckScalewayServerState("scaleway_server.base", "stopped"),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccCheckScalewayServerConfig_State, "running"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerID("scaleway_server.base", &serverID),
+					testAccCheckScalewayServerState("scaleway_server.base", "running"),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccCheckScalewayServerConfig_State, "standby"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerID("scaleway_server.base", &serverID),
+					testAccCheckScalewayServerState("scaleway_server.base", "standby"),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(testAccCheckScalewayServerConfig_State, "stopped"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerID("scaleway_server.base", &serverID),
+					testAccCheckScalewayServerState("scaleway_server.base", "stopped"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccScalewayServer_Bootscript(t *testing.T) {
+	var serverID string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScalewayServerDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewayServerConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerID("scaleway_server.base", &serverID),
+					resource.TestCheckResourceAttrSet("scaleway_server.base", "bootscript"),
+					testAccCheckScalewayServerRebooted("scaleway_server.base", false),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckScalewayServerConfig_Bootscript,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayServerID("scaleway_server.base", &serverID),
+					resource.TestCheckResourceAttrPair(
+						"scaleway_server.base", "bootscript", "data.scaleway_bootscript.rescue", "id"),
+					testAccCheckScalewayServerState("scaleway_server.base", "running"),
+					testAccCheckScalewayServerRebooted("scaleway_server.base", true),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckScalewayServerDNS(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Unknown resource: %s", n)
+		}
+
+		if expected := rs.Primary.ID + ".pub.cloud.scaleway.com"; rs.Primary.Attributes["public_dns"] != expected {
+			return fmt.Errorf("Expected public_dns to be %q but got %q", expected, rs.Primary.Attributes["public_dns"])
+		}
+		if expected := rs.Primary.ID + ".priv.cloud.scaleway.com"; rs.Primary.Attributes["private_dns"] != expected {
+			return fmt.Errorf("Expected private_dns to be %q but got %q", expected, rs.Primary.Attributes["private_dns"])
+		}
+		return nil
+	}
+}
+
+func testAccCheckScalewayServerState(n, state string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Unknown resource: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*Client).scaleway
+		server, err := client.GetServer(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if server.State != serverStates[state] {
+			return fmt.Errorf("Expected server to be %q but got %q", serverStates[state], server.State)
+		}
+		if rs.Primary.Attributes["state"] != state {
+			return fmt.Errorf("Expected state to be %q but got %q", state, rs.Primary.Attributes["state"])
+		}
+		return nil
+	}
+}
+
+// testAccCheckScalewayServerRebooted checks whether the server has been rebooted.
+// Scaleway does not expose the actions performed on a server, so the check only
+// runs against the fake API.
+func testAccCheckScalewayServerRebooted(n string, rebooted bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Unknown resource: %s", n)
+		}
+		if testAccFakeAPI == nil {
+			return nil
+		}
+
+		found := false
+		for _, action := range testAccFakeAPI.serverActions(rs.Primary.ID) {
+			found = found || action == "reboot"
+		}
+		if found != rebooted {
+			return fmt.Errorf("Expected server rebooted to be %t, got %t", rebooted, found)
+		}
+		return nil
+	}
+}
+
+// testAccCheckScalewayServerID records the ID of the server on the first call,
+// and fails if the server has been replaced on later calls.
+func testAccCheckScalewayServerID(n string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Unknown resource: %s", n)
+		}
+
+		if *id == "" {
+			*id = rs.Primary.ID
+		} else if *id != rs.Primary.ID {
+			return fmt.Errorf("Server %q has been replaced by %q", *id, rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
 func testAccCheckScalewayServerDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*Client).scaleway
 
@@ -356,6 +817,52 @@ resource "scaleway_server" "base" {
   }
 }`, armImageIdentifier)
 
+var testAccCheckScalewayServerConfig_UserData = fmt.Sprintf(`
+resource "scaleway_server" "base" {
+  name = "test"
+  # ubuntu 14.04
+  image = "%s"
+  type = "C1"
+  tags = [ "terraform-test" ]
+
+  user_data {
+    cloud-init = "#cloud-config\n"
+    role = "web"
+  }
+}`, armImageIdentifier)
+
+var testAccCheckScalewayServerConfig_UserData_Update = fmt.Sprintf(`
+resource "scaleway_server" "base" {
+  name = "test"
+  # ubuntu 14.04
+  image = "%s"
+  type = "C1"
+  tags = [ "terraform-test" ]
+
+  user_data {
+    role = "db"
+  }
+}`, armImageIdentifier)
+
+var testAccCheckScalewayServerConfig_UserData_Resource = fmt.Sprintf(`
+resource "scaleway_server" "base" {
+  name = "test"
+  # ubuntu 14.04
+  image = "%s"
+  type = "C1"
+  tags = [ "terraform-test" ]
+
+  user_data {
+    role = "db"
+  }
+}
+
+resource "scaleway_user_data" "base" {
+  server = "${scaleway_server.base.id}"
+  key = "gopher"
+  value = "supper"
+}`, armImageIdentifier)
+
 var testAccCheckScalewayServerConfig_SecurityGroup = fmt.Sprintf(`
 resource "scaleway_security_group" "blue" {
   name = "blue"
@@ -395,3 +902,218 @@ resource "scaleway_server" "base" {
   tags = [ "terraform-test" ]
   security_group = "${scaleway_security_group.red.id}"
 }`, armImageIdentifier)
+
+var testAccCheckScalewayServerConfig_Type = `
+data "scaleway_image" "ubuntu" {
+  name_filter = "Xenial"
+  architecture = "x86_64"
+  most_recent = true
+}
+
+resource "scaleway_server" "base" {
+  name = "test"
+  image = "${data.scaleway_image.ubuntu.id}"
+  type = "%s"
+  tags = [ "terraform-test" ]
+}`
+
+var testAccCheckScalewayServerConfig_State = fmt.Sprintf(`
+resource "scaleway_server" "base" {
+  name = "test"
+  # ubuntu 14.04
+  image = "%s"
+  type = "C1"
+  tags = [ "terraform-test" ]
+  state = "%%s"
+}`, armImageIdentifier)
+
+var testAccCheckScalewayServerConfig_Bootscript = fmt.Sprintf(`
+data "scaleway_bootscript" "rescue" {
+  architecture = "arm"
+  name_filter = "Rescue"
+}
+
+resource "scaleway_server" "base" {
+  name = "test"
+  # ubuntu 14.04
+  image = "%s"
+  type = "C1"
+  tags = [ "terraform-test" ]
+  bootscript = "${data.scaleway_bootscript.rescue.id}"
+  reboot_on_change = true
+}`, armImageIdentifier)
+
+var testAccCheckScalewayServerVolumeConfig_Update = fmt.Sprintf(`
+resource "scaleway_server" "base" {
+  name = "test"
+  # ubuntu 14.04
+  image = "%s"
+  type = "C1"
+  tags = [ "terraform-test" ]
+
+  volume {
+    size_in_gb = 30
+    type = "l_ssd"
+  }
+
+  volume {
+    size_in_gb = 40
+    type = "l_ssd"
+  }
+}`, armImageIdentifier)
+
+var testAccCheckScalewayServerVolumeConfig_Resize = fmt.Sprintf(`
+resource "scaleway_server" "base" {
+  name = "test"
+  # ubuntu 14.04
+  image = "%s"
+  type = "C1"
+  tags = [ "terraform-test" ]
+
+  volume {
+    size_in_gb = 30
+    type = "l_ssd"
+  }
+
+  volume {
+    size_in_gb = 50
+    type = "l_ssd"
+  }
+}`, armImageIdentifier)
+
+var testAccCheckScalewayServerConfig_RootVolume = fmt.Sprintf(`
+resource "scaleway_server" "base" {
+  name = "test"
+  # ubuntu 14.04
+  image = "%s"
+  type = "C1"
+  tags = [ "terraform-test" ]
+
+  root_volume {
+    size_in_gb = 60
+    type = "l_ssd"
+    delete_on_termination = false
+  }
+}`, armImageIdentifier)
+
+var testAccCheckScalewayServerConfig_DetachVolumesOnDestroy = fmt.Sprintf(`
+resource "scaleway_server" "base" {
+  name = "test"
+  # ubuntu 14.04
+  image = "%s"
+  type = "C1"
+  tags = [ "terraform-test" ]
+  volumes_on_destroy = "detach"
+
+  volume {
+    size_in_gb = 20
+    type = "l_ssd"
+  }
+}`, armImageIdentifier)
+
+var testAccCheckScalewayServerConfig_WaitForBooted = fmt.Sprintf(`
+resource "scaleway_server" "base" {
+  name = "test"
+  # ubuntu 14.04
+  image = "%s"
+  type = "C1"
+  tags = [ "terraform-test" ]
+  wait_for = "booted"
+}`, armImageIdentifier)
+
+var testAccCheckScalewayServerConfig_RootVolumeSnapshot = `
+data "scaleway_bootscript" "base" {
+  architecture = "arm"
+  name_filter = "Rescue"
+}
+
+resource "scaleway_volume" "base" {
+  name = "test"
+  size_in_gb = 20
+  type = "l_ssd"
+}
+
+resource "scaleway_snapshot" "base" {
+  name = "test"
+  volume = "${scaleway_volume.base.id}"
+}
+
+resource "scaleway_server" "base" {
+  name = "test"
+  root_volume_snapshot = "${scaleway_snapshot.base.id}"
+  bootscript = "${data.scaleway_bootscript.base.id}"
+  type = "C1"
+  tags = [ "terraform-test" ]
+}`
+
+func TestResourceScalewayServerCreate_WaitError(t *testing.T) {
+	fake := newFakeScalewayAPI()
+	ipAttached := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		// servers never come up, and fail to be read once created
+		if r.Method == "GET" && len(path) == 3 && path[1] == "servers" {
+			fakeError(w, http.StatusBadRequest, "invalid_request_error", "server is broken")
+			return
+		}
+		if r.Method != "GET" && len(path) == 3 && path[1] == "ips" {
+			ipAttached = true
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	client := testProviderClient(t, server.URL+"/compute")
+
+	defer func(interval time.Duration) { stateRefreshInterval = interval }(stateRefreshInterval)
+	stateRefreshInterval = 10 * time.Millisecond
+
+	ip, err := client.scaleway.NewIP()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	raw, err := config.NewRawConfig(map[string]interface{}{
+		"name":      "test",
+		"image":     armImageIdentifier,
+		"type":      "C1",
+		"public_ip": ip.IP.Address,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err := resourceScalewayServer().Diff(nil, terraform.NewResourceConfig(raw))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := resourceScalewayServer().Apply(nil, diff, client); err == nil {
+		t.Fatal("Expected creating a server which does not come up to fail")
+	}
+	if ipAttached {
+		t.Error("Expected the public IP not to be attached to a server which did not come up")
+	}
+}
+
+func TestMatchServerVolumes(t *testing.T) {
+	cases := []struct {
+		old, new []string
+		matches  []int
+		changed  []int
+	}{
+		{[]string{"20", "0", "30"}, []string{"20", "0", "30"}, []int{0, 1, 2}, nil},
+		{[]string{"20", "0", "30"}, []string{"30", "40"}, []int{2, -1}, nil},
+		{[]string{"20", "30"}, []string{"20", "10", "30"}, []int{0, -1, 1}, nil},
+		{[]string{"20", "30"}, []string{"30", "20"}, []int{1, 0}, nil},
+		{[]string{"20", "30"}, []string{"20", "40"}, []int{0, -1}, []int{1}},
+		{[]string{"20", "30", "30"}, []string{"40", "30", "30"}, []int{-1, 1, 2}, []int{0}},
+		{[]string{"20", "20"}, []string{"20"}, []int{0}, nil},
+		{[]string{"20", "", "30"}, []string{"20", "10", "30"}, []int{0, -1, 2}, nil},
+		{[]string{""}, []string{"0"}, []int{-1}, nil},
+	}
+	for _, c := range cases {
+		matches, changed := matchServerVolumes(c.old, c.new)
+		if fmt.Sprint(matches) != fmt.Sprint(c.matches) || fmt.Sprint(changed) != fmt.Sprint(c.changed) {
+			t.Errorf("Expected volumes %v changed to %v to match %v and change %v, got %v and %v", c.old, c.new, c.matches, c.changed, matches, changed)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package scaleway
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/nicolai86/scaleway-sdk/api"
+)
+
+func resourceScalewaySnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceScalewaySnapshotCreate,
+		Read:   resourceScalewaySnapshotRead,
+		Delete: resourceScalewaySnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			State: importRegionalResource,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": regionSchema(),
+			"volume": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "the volume to snapshot",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "the name of the snapshot",
+			},
+			"size_in_gb": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "the size of the snapshot in GB",
+			},
+			"volume_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the type of backing storage",
+			},
+			"base_volume": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the volume the snapshot was taken from",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the snapshot state (snapshotting, available)",
+			},
+			"creation_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "date when the snapshot was created",
+			},
+		},
+	}
+}
+
+func resourceScalewaySnapshotCreate(d *schema.ResourceData, m interface{}) error {
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+
+	volumeID := d.Get("volume").(string)
+	scalewayMutexKV.Lock(volumeID)
+	defer scalewayMutexKV.Unlock(volumeID)
+
+	snapshotID, err := scaleway.PostSnapshot(volumeID, d.Get("name").(string))
+	if err != nil {
+		return fmt.Errorf("Error creating snapshot: %q", err)
+	}
+	d.SetId(snapshotID)
+
+	if err := waitForSnapshotState(scaleway, snapshotID, "available", d.Timeout(schema.TimeoutCreate)); err != nil {
+		return err
+	}
+
+	return resourceScalewaySnapshotRead(d, m)
+}
+
+func resourceScalewaySnapshotRead(d *schema.ResourceData, m interface{}) error {
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	d.Set("region", scaleway.Region)
+	snapshot, err := scaleway.GetSnapshot(d.Id())
+	if err != nil {
+		if serr, ok := err.(api.ScalewayAPIError); ok {
+			log.Printf("[DEBUG] Error reading snapshot: %q\n", serr.APIMessage)
+
+			if serr.StatusCode == 404 {
+				d.SetId("")
+				return nil
+			}
+		}
+
+		return err
+	}
+
+	d.Set("name", snapshot.Name)
+	// the base volume might have been deleted since the snapshot was taken
+	if snapshot.BaseVolume.Identifier != "" {
+		d.Set("volume", snapshot.BaseVolume.Identifier)
+	}
+	d.Set("base_volume", snapshot.BaseVolume.Identifier)
+	d.Set("size_in_gb", snapshot.Size/gb)
+	d.Set("volume_type", snapshot.VolumeType)
+	d.Set("state", snapshot.State)
+	d.Set("creation_date", snapshot.CreationDate)
+	return nil
+}
+
+func resourceScalewaySnapshotDelete(d *schema.ResourceData, m interface{}) error {
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+
+	err = scaleway.DeleteSnapshot(d.Id())
+	if err != nil {
+		if serr, ok := err.(api.ScalewayAPIError); ok {
+			if serr.StatusCode == 404 {
+				d.SetId("")
+				return nil
+			}
+		}
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,124 @@
+package scaleway
+
+import (
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func init() {
+	resource.AddTestSweepers("scaleway_snapshot", &resource.Sweeper{
+		Name: "scaleway_snapshot",
+		F:    testSweepSnapshot,
+	})
+}
+
+func testSweepSnapshot(region string) error {
+	client, err := sharedClientForRegion(region)
+	if err != nil {
+		return fmt.Errorf("error getting client: %s", err)
+	}
+
+	scaleway := client.(*Client).scaleway
+	log.Printf("[DEBUG] Destroying the snapshots in (%s)", region)
+
+	snapshots, err := scaleway.GetSnapshots()
+	if err != nil {
+		return fmt.Errorf("Error describing snapshots in Sweeper: %s", err)
+	}
+
+	for _, snapshot := range *snapshots {
+		if err := scaleway.DeleteSnapshot(snapshot.Identifier); err != nil {
+			return fmt.Errorf("Error deleting snapshot in Sweeper: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func TestAccScalewaySnapshot_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScalewaySnapshotDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewaySnapshotConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewaySnapshotExists("scaleway_snapshot.test"),
+					resource.TestCheckResourceAttr(
+						"scaleway_snapshot.test", "name", "test"),
+					resource.TestCheckResourceAttr(
+						"scaleway_snapshot.test", "size_in_gb", "2"),
+					resource.TestCheckResourceAttr(
+						"scaleway_snapshot.test", "volume_type", "l_ssd"),
+					resource.TestCheckResourceAttr(
+						"scaleway_snapshot.test", "state", "available"),
+					resource.TestCheckResourceAttrPair(
+						"scaleway_snapshot.test", "base_volume", "scaleway_volume.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckScalewaySnapshotDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client).scaleway
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "scaleway_snapshot" {
+			continue
+		}
+
+		_, err := client.GetSnapshot(rs.Primary.ID)
+
+		if err == nil {
+			return fmt.Errorf("Snapshot still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckScalewaySnapshotExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Snapshot ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client).scaleway
+		snapshot, err := client.GetSnapshot(rs.Primary.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if snapshot.Identifier != rs.Primary.ID {
+			return fmt.Errorf("Record not found")
+		}
+
+		return nil
+	}
+}
+
+var testAccCheckScalewaySnapshotConfig = `
+resource "scaleway_volume" "test" {
+  name = "test"
+  size_in_gb = 2
+  type = "l_ssd"
+}
+
+resource "scaleway_snapshot" "test" {
+  name = "test"
+  volume = "${scaleway_volume.test.id}"
+}
+`
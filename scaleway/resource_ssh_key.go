@@ -0,0 +1,155 @@
+package scaleway
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/nicolai86/scaleway-sdk/api"
+)
+
+func resourceScalewaySSHKey() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceScalewaySSHKeyCreate,
+		Read:   resourceScalewaySSHKeyRead,
+		Delete: resourceScalewaySSHKeyDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"key": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				StateFunc: func(v interface{}) string {
+					return strings.TrimSpace(v.(string))
+				},
+				Description: "the public SSH key to add to the account",
+			},
+			"fingerprint": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the fingerprint of the SSH key",
+			},
+		},
+	}
+}
+
+// userSSHKeys returns the SSH keys of the user, stripped of their computed fingerprints
+// so they can be sent back in a PatchUserSSHKey request.
+func userSSHKeys(user *api.ScalewayUserDefinition) []api.ScalewayKeyDefinition {
+	keys := []api.ScalewayKeyDefinition{}
+	for _, key := range user.SSHPublicKeys {
+		keys = append(keys, api.ScalewayKeyDefinition{Key: key.Key})
+	}
+	return keys
+}
+
+func resourceScalewaySSHKeyCreate(d *schema.ResourceData, m interface{}) error {
+	scaleway := m.(*Client).scaleway
+
+	// SSH keys are patched as a whole list, so modifications of the user's keys
+	// must not overlap
+	userID, err := scaleway.GetUserID()
+	if err != nil {
+		return err
+	}
+	scalewayMutexKV.Lock(userID)
+	defer scalewayMutexKV.Unlock(userID)
+
+	user, err := scaleway.GetUser()
+	if err != nil {
+		return err
+	}
+
+	publicKey := strings.TrimSpace(d.Get("key").(string))
+	for _, key := range user.SSHPublicKeys {
+		if strings.TrimSpace(key.Key) == publicKey {
+			return fmt.Errorf("SSH key with fingerprint %q already exists", key.Fingerprint)
+		}
+	}
+
+	req := api.ScalewayUserPatchSSHKeyDefinition{
+		SSHPublicKeys: append(userSSHKeys(user), api.ScalewayKeyDefinition{Key: publicKey}),
+	}
+	if err := scaleway.PatchUserSSHKey(user.ID, req); err != nil {
+		return err
+	}
+
+	user, err = scaleway.GetUser()
+	if err != nil {
+		return err
+	}
+	for _, key := range user.SSHPublicKeys {
+		if strings.TrimSpace(key.Key) == publicKey {
+			d.SetId(key.Fingerprint)
+			break
+		}
+	}
+
+	if d.Id() == "" {
+		return fmt.Errorf("Failed to find created SSH key")
+	}
+
+	return resourceScalewaySSHKeyRead(d, m)
+}
+
+func resourceScalewaySSHKeyRead(d *schema.ResourceData, m interface{}) error {
+	scaleway := m.(*Client).scaleway
+
+	user, err := scaleway.GetUser()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range user.SSHPublicKeys {
+		if key.Fingerprint == d.Id() {
+			d.Set("key", strings.TrimSpace(key.Key))
+			d.Set("fingerprint", key.Fingerprint)
+			return nil
+		}
+	}
+
+	log.Printf("[DEBUG] SSH key %q not found\n", d.Id())
+	d.SetId("")
+	return nil
+}
+
+func resourceScalewaySSHKeyDelete(d *schema.ResourceData, m interface{}) error {
+	scaleway := m.(*Client).scaleway
+
+	// SSH keys are patched as a whole list, so modifications of the user's keys
+	// must not overlap
+	userID, err := scaleway.GetUserID()
+	if err != nil {
+		return err
+	}
+	scalewayMutexKV.Lock(userID)
+	defer scalewayMutexKV.Unlock(userID)
+
+	user, err := scaleway.GetUser()
+	if err != nil {
+		return err
+	}
+
+	keys := []api.ScalewayKeyDefinition{}
+	for _, key := range user.SSHPublicKeys {
+		if key.Fingerprint != d.Id() {
+			keys = append(keys, api.ScalewayKeyDefinition{Key: key.Key})
+		}
+	}
+
+	if len(keys) != len(user.SSHPublicKeys) {
+		req := api.ScalewayUserPatchSSHKeyDefinition{
+			SSHPublicKeys: keys,
+		}
+		if err := scaleway.PatchUserSSHKey(user.ID, req); err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,87 @@
+package scaleway
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccScalewaySSHKey_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScalewaySSHKeyDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewaySSHKeyConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewaySSHKeyExists("scaleway_ssh_key.test"),
+					resource.TestCheckResourceAttr(
+						"scaleway_ssh_key.test", "key", testAccScalewaySSHKey),
+					resource.TestCheckResourceAttrSet(
+						"scaleway_ssh_key.test", "fingerprint"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckScalewaySSHKeyDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client).scaleway
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "scaleway_ssh_key" {
+			continue
+		}
+
+		user, err := client.GetUser()
+		if err != nil {
+			return err
+		}
+
+		for _, key := range user.SSHPublicKeys {
+			if key.Fingerprint == rs.Primary.ID {
+				return fmt.Errorf("SSH key still exists")
+			}
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckScalewaySSHKeyExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No SSH key ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client).scaleway
+		user, err := client.GetUser()
+		if err != nil {
+			return err
+		}
+
+		for _, key := range user.SSHPublicKeys {
+			if key.Fingerprint == rs.Primary.ID {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Record not found")
+	}
+}
+
+var testAccScalewaySSHKey = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQDS6yoHtc21zvaEFzt770g64MAPylTFD+x8OBzvSUWm9lgsv1VtIgbnVYvOUpcaDjLAaOAAqRo07DHw63SmT1LtfD/Gubty/x7jYUxdx/ok+4wAaokmWn92be9LbzlahiU3yMnm6xdfaFhKh0/Z6AzYH687n1oV11IFcXH0m8G+qK6GG+GnCoVtOdpDtaqM99G23+5KG97LBealiI98gHR1Y/cOjzx3TkL2lUVqqeRaCi4vRfNZc43fE5GxF9FJ6jwhlT4QvDdIYMXUl/saFWJa9AVVnhr1qOXBXdd9ZwoG6dS6omYRCfOaA+KSWLHC2Q8SQ0uKIxwq1ErkFEAzB1pz terraform-test"
+
+var testAccCheckScalewaySSHKeyConfig = fmt.Sprintf(`
+resource "scaleway_ssh_key" "test" {
+  key = "%s"
+}`, testAccScalewaySSHKey)
@@ -0,0 +1,149 @@
+package scaleway
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/nicolai86/scaleway-sdk/api"
+)
+
+func resourceScalewayUserData() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceScalewayUserDataCreate,
+		Read:   resourceScalewayUserDataRead,
+		Update: resourceScalewayUserDataUpdate,
+		Delete: resourceScalewayUserDataDelete,
+		Importer: &schema.ResourceImporter{
+			State: importRegionalResource,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"region": regionSchema(),
+			"server": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "the server the user data is associated with",
+			},
+			"key": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "the key of the user data",
+			},
+			"value": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "the value of the user data",
+			},
+		},
+	}
+}
+
+// parseUserDataID splits a user data ID of the form server/key.
+func parseUserDataID(id string) (serverID, key string, err error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("Invalid user data ID %q, expected server/key", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func resourceScalewayUserDataCreate(d *schema.ResourceData, m interface{}) error {
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+
+	serverID, key := d.Get("server").(string), d.Get("key").(string)
+	if err := scaleway.PatchUserdata(serverID, key, []byte(d.Get("value").(string)), false); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", serverID, key))
+	return resourceScalewayUserDataRead(d, m)
+}
+
+func resourceScalewayUserDataRead(d *schema.ResourceData, m interface{}) error {
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	d.Set("region", scaleway.Region)
+
+	serverID, key, err := parseUserDataID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	keys, err := scaleway.GetUserdatas(serverID, false)
+	if err != nil {
+		if serr, ok := err.(api.ScalewayAPIError); ok {
+			log.Printf("[DEBUG] Error reading user data: %q\n", serr.APIMessage)
+
+			if serr.StatusCode == 404 {
+				d.SetId("")
+				return nil
+			}
+		}
+		return err
+	}
+
+	found := false
+	for _, k := range keys.UserData {
+		found = found || k == key
+	}
+	if !found {
+		log.Printf("[DEBUG] User data %q not found on server %q\n", key, serverID)
+		d.SetId("")
+		return nil
+	}
+
+	value, err := scaleway.GetUserdata(serverID, key, false)
+	if err != nil {
+		return err
+	}
+
+	d.Set("server", serverID)
+	d.Set("key", key)
+	d.Set("value", value.String())
+	return nil
+}
+
+func resourceScalewayUserDataUpdate(d *schema.ResourceData, m interface{}) error {
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+
+	if d.HasChange("value") {
+		serverID, key := d.Get("server").(string), d.Get("key").(string)
+		if err := scaleway.PatchUserdata(serverID, key, []byte(d.Get("value").(string)), false); err != nil {
+			return err
+		}
+	}
+
+	return resourceScalewayUserDataRead(d, m)
+}
+
+func resourceScalewayUserDataDelete(d *schema.ResourceData, m interface{}) error {
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+
+	err = scaleway.DeleteUserdata(d.Get("server").(string), d.Get("key").(string), false)
+	if err != nil {
+		if serr, ok := err.(api.ScalewayAPIError); ok {
+			if serr.StatusCode == 404 {
+				d.SetId("")
+				return nil
+			}
+		}
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,114 @@
+package scaleway
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccScalewayUserData_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckScalewayUserDataDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckScalewayUserDataConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayUserDataExists("scaleway_user_data.base"),
+					resource.TestCheckResourceAttr(
+						"scaleway_user_data.base", "key", "gopher"),
+					resource.TestCheckResourceAttr(
+						"scaleway_user_data.base", "value", "supper"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckScalewayUserDataConfig_Update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckScalewayUserDataExists("scaleway_user_data.base"),
+					resource.TestCheckResourceAttr(
+						"scaleway_user_data.base", "value", "dinner"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckScalewayUserDataDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Client).scaleway
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "scaleway_user_data" {
+			continue
+		}
+
+		_, err := client.GetUserdata(rs.Primary.Attributes["server"], rs.Primary.Attributes["key"], false)
+
+		if err == nil {
+			return fmt.Errorf("User data still exists")
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckScalewayUserDataExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No User Data ID is set")
+		}
+
+		client := testAccProvider.Meta().(*Client).scaleway
+		value, err := client.GetUserdata(rs.Primary.Attributes["server"], rs.Primary.Attributes["key"], false)
+
+		if err != nil {
+			return err
+		}
+
+		if value.String() != rs.Primary.Attributes["value"] {
+			return fmt.Errorf("User data has wrong value: %q", value.String())
+		}
+
+		return nil
+	}
+}
+
+var testAccCheckScalewayUserDataConfig = fmt.Sprintf(`
+resource "scaleway_server" "base" {
+  name = "test"
+  # ubuntu 14.04
+  image = "%s"
+  type = "C1"
+  tags = [ "terraform-test" ]
+  state = "stopped"
+}
+
+resource "scaleway_user_data" "base" {
+  server = "${scaleway_server.base.id}"
+  key = "gopher"
+  value = "supper"
+}`, armImageIdentifier)
+
+var testAccCheckScalewayUserDataConfig_Update = fmt.Sprintf(`
+resource "scaleway_server" "base" {
+  name = "test"
+  # ubuntu 14.04
+  image = "%s"
+  type = "C1"
+  tags = [ "terraform-test" ]
+  state = "stopped"
+}
+
+resource "scaleway_user_data" "base" {
+  server = "${scaleway_server.base.id}"
+  key = "gopher"
+  value = "dinner"
+}`, armImageIdentifier)
@@ -3,7 +3,9 @@ package scaleway
 import (
 	"fmt"
 	"log"
+	"time"
 
+	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/nicolai86/scaleway-sdk/api"
 )
@@ -17,10 +19,14 @@ func resourceScalewayVolume() *schema.Resource {
 		Update: resourceScalewayVolumeUpdate,
 		Delete: resourceScalewayVolumeDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: importRegionalResource,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(defaultTimeout),
 		},
 
 		Schema: map[string]*schema.Schema{
+			"region": regionSchema(),
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -54,10 +60,10 @@ func resourceScalewayVolume() *schema.Resource {
 }
 
 func resourceScalewayVolumeCreate(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
-
-	mu.Lock()
-	defer mu.Unlock()
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
 	size := uint64(d.Get("size_in_gb").(int)) * gb
 	req := api.ScalewayVolumeDefinition{
@@ -75,7 +81,11 @@ func resourceScalewayVolumeCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceScalewayVolumeRead(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	d.Set("region", scaleway.Region)
 	volume, err := scaleway.GetVolume(d.Id())
 	if err != nil {
 		if serr, ok := err.(api.ScalewayAPIError); ok {
@@ -100,10 +110,13 @@ func resourceScalewayVolumeRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceScalewayVolumeUpdate(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	scalewayMutexKV.Lock(d.Id())
+	defer scalewayMutexKV.Unlock(d.Id())
 
 	var req api.ScalewayVolumePutDefinition
 	if d.HasChange("name") {
@@ -120,21 +133,51 @@ func resourceScalewayVolumeUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceScalewayVolumeDelete(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
-	mu.Lock()
-	defer mu.Unlock()
+	scalewayMutexKV.Lock(d.Id())
+	defer scalewayMutexKV.Unlock(d.Id())
+
+	// volumes which were just detached from a server can not be deleted right
+	// away, while volumes which are still attached can not be deleted at all
+	timeout := d.Timeout(schema.TimeoutDelete)
+	if timeout > volumeDetachTimeout {
+		timeout = volumeDetachTimeout
+	}
+	err = resource.Retry(timeout, func() *resource.RetryError {
+		err := scaleway.DeleteVolume(d.Id())
+		if err == nil {
+			return nil
+		}
 
-	err := scaleway.DeleteVolume(d.Id())
-	if err != nil {
 		if serr, ok := err.(api.ScalewayAPIError); ok {
+			log.Printf("[DEBUG] Error deleting volume: %q\n", serr.APIMessage)
+
 			if serr.StatusCode == 404 {
-				d.SetId("")
 				return nil
 			}
+			if serr.StatusCode == 400 && volumeDetached(scaleway, d.Id()) {
+				return resource.RetryableError(fmt.Errorf("Waiting for volume to be detached: %q", serr.APIMessage))
+			}
 		}
+
+		return resource.NonRetryableError(err)
+	})
+	if err != nil {
 		return err
 	}
 	d.SetId("")
 	return nil
 }
+
+// volumeDetachTimeout bounds the wait for Scaleway to finish detaching a volume.
+const volumeDetachTimeout = 2 * time.Minute
+
+// volumeDetached returns whether the volume is no longer attached to a server.
+func volumeDetached(scaleway *api.ScalewayAPI, volumeID string) bool {
+	volume, err := scaleway.GetVolume(volumeID)
+	return err == nil && volume.Server == nil
+}
@@ -3,9 +3,7 @@ package scaleway
 import (
 	"fmt"
 	"log"
-	"time"
 
-	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/nicolai86/scaleway-sdk/api"
 )
@@ -15,7 +13,12 @@ func resourceScalewayVolumeAttachment() *schema.Resource {
 		Create: resourceScalewayVolumeAttachmentCreate,
 		Read:   resourceScalewayVolumeAttachmentRead,
 		Delete: resourceScalewayVolumeAttachmentDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(defaultTimeout),
+			Delete: schema.DefaultTimeout(defaultTimeout),
+		},
 		Schema: map[string]*schema.Schema{
+			"region": regionSchema(),
 			"server": {
 				Type:        schema.TypeString,
 				Required:    true,
@@ -35,10 +38,14 @@ func resourceScalewayVolumeAttachment() *schema.Resource {
 var errVolumeAlreadyAttached = fmt.Errorf("Scaleway volume already attached")
 
 func resourceScalewayVolumeAttachmentCreate(d *schema.ResourceData, m interface{}) error {
-	mu.Lock()
-	defer mu.Unlock()
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
-	scaleway := m.(*Client).scaleway
+	serverID := d.Get("server").(string)
+	scalewayMutexKV.Lock(serverID)
+	defer scalewayMutexKV.Unlock(serverID)
 
 	vol, err := scaleway.GetVolume(d.Get("volume").(string))
 	if err != nil {
@@ -49,86 +56,28 @@ func resourceScalewayVolumeAttachmentCreate(d *schema.ResourceData, m interface{
 		return errVolumeAlreadyAttached
 	}
 
-	serverID := d.Get("server").(string)
-
 	server, err := scaleway.GetServer(serverID)
 	if err != nil {
 		fmt.Printf("Failed getting server: %q", err)
 		return err
 	}
 
-	var startServerAgain = false
-	// volumes can only be modified when the server is powered off
-	if server.State != "stopped" {
-		startServerAgain = true
-
-		if err := scaleway.PostServerAction(server.Identifier, "poweroff"); err != nil {
-			return err
-		}
-	}
-	if err := waitForServerState(scaleway, server.Identifier, "stopped"); err != nil {
+	volumes := append(sortedServerVolumes(server), *vol)
+	if err := patchServerVolumes(scaleway, serverID, volumes, d.Timeout(schema.TimeoutCreate)); err != nil {
 		return err
 	}
 
-	volumes := make(map[string]api.ScalewayVolume)
-	for i, volume := range server.Volumes {
-		volumes[i] = volume
-	}
-
-	volumes[fmt.Sprintf("%d", len(volumes)+1)] = *vol
-
-	// the API request requires most volume attributes to be unset to succeed
-	for k, v := range volumes {
-		v.Size = 0
-		v.CreationDate = ""
-		v.Organization = ""
-		v.ModificationDate = ""
-		v.VolumeType = ""
-		v.Server = nil
-		v.ExportURI = ""
-
-		volumes[k] = v
-	}
-
-	if err := resource.Retry(serverWaitTimeout, func() *resource.RetryError {
-		var req = api.ScalewayServerPatchDefinition{
-			Volumes: &volumes,
-		}
-		err := scaleway.PatchServer(serverID, req)
-
-		if err == nil {
-			return nil
-		}
-
-		if serr, ok := err.(api.ScalewayAPIError); ok {
-			log.Printf("[DEBUG] Error patching server: %q\n", serr.APIMessage)
-
-			if serr.StatusCode == 400 {
-				return resource.RetryableError(fmt.Errorf("Waiting for server update to succeed: %q", serr.APIMessage))
-			}
-		}
-
-		return resource.NonRetryableError(err)
-	}); err != nil {
-		return err
-	}
-
-	if startServerAgain {
-		if err := scaleway.PostServerAction(serverID, "poweron"); err != nil {
-			return err
-		}
-		if err := waitForServerState(scaleway, serverID, "running"); err != nil {
-			return err
-		}
-	}
-
 	d.SetId(fmt.Sprintf("scaleway-server:%s/volume/%s", serverID, d.Get("volume").(string)))
 
 	return resourceScalewayVolumeAttachmentRead(d, m)
 }
 
 func resourceScalewayVolumeAttachmentRead(d *schema.ResourceData, m interface{}) error {
-	scaleway := m.(*Client).scaleway
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
+	d.Set("region", scaleway.Region)
 
 	server, err := scaleway.GetServer(d.Get("server").(string))
 	if err != nil {
@@ -166,86 +115,31 @@ func resourceScalewayVolumeAttachmentRead(d *schema.ResourceData, m interface{})
 	return nil
 }
 
-const serverWaitTimeout = 5 * time.Minute
-
 func resourceScalewayVolumeAttachmentDelete(d *schema.ResourceData, m interface{}) error {
-	mu.Lock()
-	defer mu.Unlock()
-
-	scaleway := m.(*Client).scaleway
-
-	var startServerAgain = false
+	scaleway, err := m.(*Client).forResource(d)
+	if err != nil {
+		return err
+	}
 
 	serverID := d.Get("server").(string)
+	scalewayMutexKV.Lock(serverID)
+	defer scalewayMutexKV.Unlock(serverID)
 
 	server, err := scaleway.GetServer(serverID)
 	if err != nil {
 		return err
 	}
 
-	// volumes can only be modified when the server is powered off
-	if server.State != "stopped" {
-		startServerAgain = true
-		if err := scaleway.PostServerAction(server.Identifier, "poweroff"); err != nil {
-			return err
-		}
-	}
-	if err := waitForServerState(scaleway, server.Identifier, "stopped"); err != nil {
-		return err
-	}
-
-	volumes := make(map[string]api.ScalewayVolume)
-	for _, volume := range server.Volumes {
+	volumes := []api.ScalewayVolume{}
+	for _, volume := range sortedServerVolumes(server) {
 		if volume.Identifier != d.Get("volume").(string) {
-			volumes[fmt.Sprintf("%d", len(volumes))] = volume
+			volumes = append(volumes, volume)
 		}
 	}
-
-	// the API request requires most volume attributes to be unset to succeed
-	for k, v := range volumes {
-		v.Size = 0
-		v.CreationDate = ""
-		v.Organization = ""
-		v.ModificationDate = ""
-		v.VolumeType = ""
-		v.Server = nil
-		v.ExportURI = ""
-
-		volumes[k] = v
-	}
-
-	if err := resource.Retry(serverWaitTimeout, func() *resource.RetryError {
-		var req = api.ScalewayServerPatchDefinition{
-			Volumes: &volumes,
-		}
-		err := scaleway.PatchServer(serverID, req)
-
-		if err == nil {
-			return nil
-		}
-
-		if serr, ok := err.(api.ScalewayAPIError); ok {
-			log.Printf("[DEBUG] Error patching server: %q\n", serr.APIMessage)
-
-			if serr.StatusCode == 400 {
-				return resource.RetryableError(fmt.Errorf("Waiting for server update to succeed: %q", serr.APIMessage))
-			}
-		}
-
-		return resource.NonRetryableError(err)
-	}); err != nil {
+	if err := patchServerVolumes(scaleway, serverID, volumes, d.Timeout(schema.TimeoutDelete)); err != nil {
 		return err
 	}
 
-	if startServerAgain {
-		if err := scaleway.PostServerAction(serverID, "poweron"); err != nil {
-			return err
-		}
-		if err := waitForServerState(scaleway, serverID, "running"); err != nil {
-			return err
-		}
-	}
-
 	d.SetId("")
 
 	return nil
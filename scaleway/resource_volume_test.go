@@ -3,10 +3,13 @@ package scaleway
 import (
 	"fmt"
 	"log"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"github.com/nicolai86/scaleway-sdk/api"
 )
 
 func init() {
@@ -136,3 +139,37 @@ resource "scaleway_volume" "test" {
   type = "l_ssd"
 }
 `
+
+func TestResourceScalewayVolumeDelete_Attached(t *testing.T) {
+	server := httptest.NewServer(newFakeScalewayAPI())
+	defer server.Close()
+	client := testProviderClient(t, server.URL+"/compute")
+
+	volumeID, err := client.scaleway.PostVolume(api.ScalewayVolumeDefinition{
+		Name: "test",
+		Size: 2 * gb,
+		Type: "l_ssd",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := client.scaleway.PostServer(api.ScalewayServerDefinition{
+		Name:           "test",
+		Image:          String(armImageIdentifier),
+		CommercialType: "C1",
+		Volumes:        map[string]string{"1": volumeID},
+	}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	state := &terraform.InstanceState{ID: volumeID}
+	diff := &terraform.InstanceDiff{Destroy: true}
+
+	start := time.Now()
+	if _, err := resourceScalewayVolume().Apply(state, diff, client); err == nil {
+		t.Fatal("Expected deleting an attached volume to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("Expected deleting an attached volume to fail right away, took %s", elapsed)
+	}
+}
@@ -0,0 +1,158 @@
+package scaleway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/nicolai86/scaleway-sdk/api"
+)
+
+// The vendored Scaleway SDK lacks some requests used by the provider. They are
+// implemented here on top of the exported SDK API.
+
+// handleHTTPError checks the status code of a response like the SDK does, and
+// returns its body
+func handleHTTPError(resp *http.Response, goodStatusCodes ...int) ([]byte, error) {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, errors.New(string(body))
+	}
+	for _, code := range goodStatusCodes {
+		if code == resp.StatusCode {
+			return body, nil
+		}
+	}
+
+	var scwError api.ScalewayAPIError
+	if err := json.Unmarshal(body, &scwError); err != nil {
+		return nil, err
+	}
+	scwError.StatusCode = resp.StatusCode
+	return nil, scwError
+}
+
+// scalewayServerTypePatchDefinition changes the commercial type of a server,
+// which api.ScalewayServerPatchDefinition does not support
+type scalewayServerTypePatchDefinition struct {
+	CommercialType string `json:"commercial_type"`
+}
+
+// patchServerType changes the commercial type of a stopped server
+func patchServerType(scaleway *api.ScalewayAPI, serverID, commercialType string) error {
+	resp, err := scaleway.PatchResponse(scaleway.ComputeAPI(), fmt.Sprintf("servers/%s", serverID), scalewayServerTypePatchDefinition{
+		CommercialType: commercialType,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = handleHTTPError(resp, http.StatusOK)
+	return err
+}
+
+// scalewayServerDefinition creates a server. Its volumes are either the identifier
+// of an existing volume or a scalewayServerVolumeDefinition of a volume to create,
+// api.ScalewayServerDefinition only supports existing volumes.
+type scalewayServerDefinition struct {
+	api.ScalewayServerDefinition
+
+	Volumes map[string]interface{} `json:"volumes,omitempty"`
+}
+
+// scalewayServerVolumeDefinition represents a volume created along with a server,
+// e.g. a root volume with a size differing from the root volume of the image
+type scalewayServerVolumeDefinition struct {
+	Name       string `json:"name,omitempty"`
+	Size       uint64 `json:"size,omitempty"`
+	VolumeType string `json:"volume_type,omitempty"`
+}
+
+// postServer creates a server and returns its identifier
+func postServer(scaleway *api.ScalewayAPI, definition scalewayServerDefinition) (string, error) {
+	definition.Organization = scaleway.Organization
+
+	resp, err := scaleway.PostResponse(scaleway.ComputeAPI(), "servers", definition)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := handleHTTPError(resp, http.StatusCreated)
+	if err != nil {
+		return "", err
+	}
+	var server api.ScalewayOneServer
+	if err := json.Unmarshal(body, &server); err != nil {
+		return "", err
+	}
+	return server.Server.Identifier, nil
+}
+
+// scalewayVolumeDefinition creates a volume, optionally restored from a snapshot,
+// which api.ScalewayVolumeDefinition does not support
+type scalewayVolumeDefinition struct {
+	api.ScalewayVolumeDefinition
+
+	// BaseSnapshot is the snapshot the volume is restored from
+	BaseSnapshot string `json:"base_snapshot,omitempty"`
+}
+
+// postVolume creates a volume and returns its identifier
+func postVolume(scaleway *api.ScalewayAPI, definition scalewayVolumeDefinition) (string, error) {
+	definition.Organization = scaleway.Organization
+	if definition.Type == "" {
+		definition.Type = "l_ssd"
+	}
+
+	resp, err := scaleway.PostResponse(scaleway.ComputeAPI(), "volumes", definition)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := handleHTTPError(resp, http.StatusCreated)
+	if err != nil {
+		return "", err
+	}
+	var volume struct {
+		Volume api.ScalewayVolume `json:"volume"`
+	}
+	if err := json.Unmarshal(body, &volume); err != nil {
+		return "", err
+	}
+	return volume.Volume.Identifier, nil
+}
+
+// getServers lists the servers of the region of the client, GetServers of the
+// SDK lists the servers of all regions.
+func getServers(scaleway *api.ScalewayAPI) ([]api.ScalewayServer, error) {
+	resp, err := scaleway.GetResponsePaginate(scaleway.ComputeAPI(), "servers", url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := handleHTTPError(resp, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	var servers api.ScalewayServers
+	if err := json.Unmarshal(body, &servers); err != nil {
+		return nil, err
+	}
+
+	for i, server := range servers.Servers {
+		servers.Servers[i].DNSPublic = server.Identifier + api.URLPublicDNS
+		servers.Servers[i].DNSPrivate = server.Identifier + api.URLPrivateDNS
+	}
+	return servers.Servers, nil
+}
@@ -27,6 +27,7 @@ func sharedClientForRegion(region string) (interface{}, error) {
 		Organization: os.Getenv("SCALEWAY_ORGANIZATION"),
 		APIKey:       os.Getenv("SCALEWAY_TOKEN"),
 		Region:       region,
+		MaxRetries:   3,
 	}
 
 	// configures a default client for the region, using the above env vars
@@ -0,0 +1,161 @@
+package scaleway
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMinBackoff    = 1 * time.Second
+	defaultMaxBackoff    = 30 * time.Second
+	defaultMaxRetryAfter = 2 * time.Minute
+)
+
+// retryTransport is a http.RoundTripper which retries requests failing because of
+// rate limiting, server errors or connection resets, using exponential backoff with jitter.
+// Requests which are not idempotent, e.g. creating a server, are only retried when
+// they were not processed by the API. Once all retries are exhausted the last
+// response or error is returned as is.
+type retryTransport struct {
+	transport     http.RoundTripper
+	maxRetries    int
+	minBackoff    time.Duration
+	maxBackoff    time.Duration
+	maxRetryAfter time.Duration
+}
+
+func newRetryTransport(transport http.RoundTripper, maxRetries int) *retryTransport {
+	return &retryTransport{
+		transport:     transport,
+		maxRetries:    maxRetries,
+		minBackoff:    defaultMinBackoff,
+		maxBackoff:    defaultMaxBackoff,
+		maxRetryAfter: defaultMaxRetryAfter,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the body needs to be buffered so it can be sent again on retries
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		// each attempt sends its own copy of the request, which must not be modified
+		attemptReq := new(http.Request)
+		*attemptReq = *req
+		if body != nil {
+			attemptReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err := t.transport.RoundTrip(attemptReq)
+		if attempt >= t.maxRetries || !shouldRetry(req.Method, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if err != nil {
+			log.Printf("[DEBUG] %s %s failed: %s, retrying in %s", req.Method, req.URL, err, wait)
+		} else {
+			log.Printf("[DEBUG] %s %s returned %d, retrying in %s", req.Method, req.URL, resp.StatusCode, wait)
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		time.Sleep(wait)
+	}
+}
+
+// backoff returns how long to wait before the next attempt. A Retry-After header
+// sent by the API takes precedence over the exponential backoff.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if wait > t.maxRetryAfter {
+				wait = t.maxRetryAfter
+			}
+			return wait
+		}
+	}
+
+	backoff := t.minBackoff << uint(attempt)
+	if backoff > t.maxBackoff || backoff <= 0 {
+		backoff = t.maxBackoff
+	}
+	// full jitter, see https://www.awsarchitectureblog.com/2015/03/backoff.html
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// shouldRetry returns whether a request can be sent again. Server errors and
+// broken connections may happen after the API processed a request, so they
+// are only retried for idempotent methods.
+func shouldRetry(method string, resp *http.Response, err error) bool {
+	idempotent := isIdempotent(method)
+	if err != nil {
+		if idempotent {
+			return isConnectionError(err)
+		}
+		return isDialError(err)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return idempotent && resp.StatusCode >= http.StatusInternalServerError
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+// isDialError returns whether the connection to the API could not be opened,
+// in which case the request was never sent.
+func isDialError(err error) bool {
+	operr, ok := err.(*net.OpError)
+	return ok && operr.Op == "dial"
+}
+
+func isConnectionError(err error) bool {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+	if nerr, ok := err.(net.Error); ok && (nerr.Temporary() || nerr.Timeout()) {
+		return true
+	}
+	return strings.Contains(err.Error(), "connection reset by peer")
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or a HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(time.Now())
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
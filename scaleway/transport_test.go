@@ -0,0 +1,209 @@
+package scaleway
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testRetryClient(maxRetries int) *http.Client {
+	transport := newRetryTransport(http.DefaultTransport, maxRetries)
+	transport.minBackoff = time.Millisecond
+	transport.maxBackoff = 5 * time.Millisecond
+	return &http.Client{Transport: transport}
+}
+
+func TestRetryTransport_RetriesTransientErrors(t *testing.T) {
+	attempts := 0
+	bodies := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest("PUT", server.URL, strings.NewReader(`{"name":"test"}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp, err := testRetryClient(3).Do(req)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+	for _, body := range bodies {
+		if body != `{"name":"test"}` {
+			t.Errorf("expected request body to be replayed, got %q", body)
+		}
+	}
+}
+
+func TestRetryTransport_GivesUp(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	resp, err := testRetryClient(2).Get(server.URL)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", resp.StatusCode)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryTransport_DoesNotRetryClientErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	resp, err := testRetryClient(3).Get(server.URL)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetryTransport_RetriesPostOnlyWhenNotProcessed(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	resp, err := testRetryClient(3).Post(server.URL, "application/json", strings.NewReader(`{"name":"test"}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", resp.StatusCode)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRetryTransport_ConnectionErrorsOfPost(t *testing.T) {
+	for _, c := range []struct {
+		err      error
+		attempts int
+	}{
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, 2},
+		{&net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}, 1},
+	} {
+		attempts := 0
+		transport := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			attempts++
+			if attempts == 1 {
+				return nil, c.err
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+		}), 3)
+		transport.minBackoff = time.Millisecond
+		transport.maxBackoff = 5 * time.Millisecond
+
+		req, err := http.NewRequest("POST", "http://scaleway.test/servers", strings.NewReader(`{"name":"test"}`))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		transport.RoundTrip(req)
+
+		if attempts != c.attempts {
+			t.Errorf("expected %d attempts after %q, got %d", c.attempts, c.err, attempts)
+		}
+	}
+}
+
+func TestRetryTransport_DoesNotModifyRequest(t *testing.T) {
+	transport := newRetryTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusTooManyRequests, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+	}), 1)
+	transport.minBackoff = time.Millisecond
+	transport.maxBackoff = 5 * time.Millisecond
+
+	req, err := http.NewRequest("PUT", "http://scaleway.test/servers/1", strings.NewReader(`{"name":"test"}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	body := req.Body
+	transport.RoundTrip(req)
+
+	if req.Body != body {
+		t.Error("expected the body of the request not to be replaced")
+	}
+}
+
+func TestRetryTransport_CapsRetryAfter(t *testing.T) {
+	transport := newRetryTransport(http.DefaultTransport, 3)
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3600"}}}
+
+	if wait := transport.backoff(0, resp); wait != defaultMaxRetryAfter {
+		t.Errorf("expected Retry-After to be capped to %s, got %s", defaultMaxRetryAfter, wait)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if wait, ok := parseRetryAfter("2"); !ok || wait != 2*time.Second {
+		t.Errorf("expected 2s, got %s", wait)
+	}
+
+	date := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	if wait, ok := parseRetryAfter(date); !ok || wait <= 0 || wait > 10*time.Second {
+		t.Errorf("expected up to 10s, got %s", wait)
+	}
+
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Error("expected invalid Retry-After to be ignored")
+	}
+}